@@ -0,0 +1,225 @@
+package hetzner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// API is the backend of the provider: the zone and record endpoints of the
+// Hetzner DNS API it builds on. Provider talks to the real API over HTTP
+// unless Provider.API is set, e.g. to a fake in the unit tests of an
+// application using the provider.
+//
+// Errors are returned as they are; return e.g. an *APIError with StatusCode
+// 404 for missing zones and records, as the real API does.
+//
+// Implementations must be safe for concurrent use: batch operations call
+// them from several goroutines at once.
+type API interface {
+	// ListZones returns a page of the zones, of the zones named name if it
+	// isn't empty. A page of 0 asks for all zones without pagination.
+	ListZones(ctx context.Context, name string, page int, perPage int) ([]APIZone, APIPagination, error)
+
+	// UpdateZone sets the name and default TTL of the zone with the ID of z.
+	UpdateZone(ctx context.Context, z APIZone) error
+
+	// ListRecords returns a page of the records of the zone with the given
+	// ID. A page of 0 asks for all records without pagination.
+	ListRecords(ctx context.Context, zoneID string, page int, perPage int) ([]APIRecord, APIPagination, error)
+
+	GetRecord(ctx context.Context, id string) (APIRecord, error)
+
+	// CreateRecord creates r, which has no ID, and returns it as stored.
+	CreateRecord(ctx context.Context, r APIRecord) (APIRecord, error)
+
+	// UpdateRecord replaces the record with the ID of r and returns it as
+	// stored.
+	UpdateRecord(ctx context.Context, r APIRecord) (APIRecord, error)
+
+	DeleteRecord(ctx context.Context, id string) error
+
+	// BulkUpdateRecords replaces the records with the IDs of records in one
+	// request. It returns the records as stored and the records that were
+	// rejected.
+	BulkUpdateRecords(ctx context.Context, records []APIRecord) ([]APIRecord, []APIRecord, error)
+}
+
+// APIZone is a zone as represented by the API.
+type APIZone struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	TTL          int    `json:"ttl"`
+	RecordsCount int    `json:"records_count"`
+}
+
+// APIRecord is a record as represented by the API. TTL is in seconds; a nil
+// TTL makes the record inherit the zone's default TTL.
+type APIRecord struct {
+	ID     string `json:"id,omitempty"`
+	ZoneID string `json:"zone_id,omitempty"`
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	TTL    *int   `json:"ttl,omitempty"`
+
+	Created  string `json:"created,omitempty"`
+	Modified string `json:"modified,omitempty"`
+}
+
+// APIPagination describes the page of a listing.
+type APIPagination struct {
+	Page         int `json:"page"`
+	LastPage     int `json:"last_page"`
+	TotalEntries int `json:"total_entries"`
+}
+
+// api returns the backend of the provider.
+func (p *Provider) api() API {
+	if p.API != nil {
+		return p.API
+	}
+	return httpAPI{p}
+}
+
+// httpAPI is the API implementation talking to the Hetzner DNS API over HTTP,
+// with the provider's retries, timeouts and circuit breaker.
+type httpAPI struct {
+	p *Provider
+}
+
+var _ API = httpAPI{}
+
+func (a httpAPI) ListZones(ctx context.Context, name string, page int, perPage int) ([]APIZone, APIPagination, error) {
+	op, query := opRead, url.Values{}
+	if name != "" {
+		op = opZoneLookup
+		query.Set("name", name)
+	}
+	if page > 0 {
+		query.Set("page", fmt.Sprint(page))
+		query.Set("per_page", fmt.Sprint(perPage))
+	}
+	path := "/zones"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	data, err := a.p.doRequest(ctx, op, "GET", path, nil)
+	if err != nil {
+		return nil, APIPagination{}, err
+	}
+
+	result := getAllZonesResponse{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, APIPagination{}, err
+	}
+	return result.Zones, result.Meta.Pagination, nil
+}
+
+func (a httpAPI) UpdateZone(ctx context.Context, z APIZone) error {
+	reqData := updateZoneRequest{Name: z.Name, TTL: z.TTL}
+	_, err := a.p.doRequest(ctx, opWrite, "PUT", fmt.Sprintf("/zones/%s", url.PathEscape(z.ID)), reqData)
+	return err
+}
+
+func (a httpAPI) ListRecords(ctx context.Context, zoneID string, page int, perPage int) ([]APIRecord, APIPagination, error) {
+	path := fmt.Sprintf("/records?zone_id=%s", url.QueryEscape(zoneID))
+	if page > 0 {
+		path += fmt.Sprintf("&page=%d&per_page=%d", page, perPage)
+	}
+	data, err := a.p.doRequest(ctx, opRead, "GET", path, nil)
+	if err != nil {
+		return nil, APIPagination{}, err
+	}
+
+	result := getAllRecordsResponse{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, APIPagination{}, err
+	}
+	return result.Records, result.Meta.Pagination, nil
+}
+
+// rawRecordLister is implemented by backends that can return the records
+// of a zone as the JSON objects they received.
+type rawRecordLister interface {
+	listRecordsRaw(ctx context.Context, zoneID string) ([]json.RawMessage, error)
+}
+
+var _ rawRecordLister = httpAPI{}
+
+// listRecordsRaw is like ListRecords for all records of the zone, but keeps
+// each record object as the API sent it, including fields APIRecord lacks.
+func (a httpAPI) listRecordsRaw(ctx context.Context, zoneID string) ([]json.RawMessage, error) {
+	data, err := a.p.doRequest(ctx, opRead, "GET", fmt.Sprintf("/records?zone_id=%s", url.QueryEscape(zoneID)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Records []json.RawMessage `json:"records"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return result.Records, nil
+}
+
+func (a httpAPI) GetRecord(ctx context.Context, id string) (APIRecord, error) {
+	data, err := a.p.doRequest(ctx, opRead, "GET", fmt.Sprintf("/records/%s", url.PathEscape(id)), nil)
+	if err != nil {
+		return APIRecord{}, err
+	}
+
+	result := getRecordResponse{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return APIRecord{}, err
+	}
+	return result.Record, nil
+}
+
+func (a httpAPI) CreateRecord(ctx context.Context, r APIRecord) (APIRecord, error) {
+	data, err := a.p.doRequest(ctx, opWrite, "POST", "/records", r)
+	if err != nil {
+		return APIRecord{}, err
+	}
+
+	result := createRecordResponse{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return APIRecord{}, err
+	}
+	return result.Record, nil
+}
+
+func (a httpAPI) UpdateRecord(ctx context.Context, r APIRecord) (APIRecord, error) {
+	id := r.ID
+	r.ID = ""
+	data, err := a.p.doRequest(ctx, opWrite, "PUT", fmt.Sprintf("/records/%s", url.PathEscape(id)), r)
+	if err != nil {
+		return APIRecord{}, err
+	}
+
+	result := updateRecordResponse{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return APIRecord{}, err
+	}
+	return result.Record, nil
+}
+
+func (a httpAPI) DeleteRecord(ctx context.Context, id string) error {
+	_, err := a.p.doRequest(ctx, opWrite, "DELETE", fmt.Sprintf("/records/%s", url.PathEscape(id)), nil)
+	return err
+}
+
+func (a httpAPI) BulkUpdateRecords(ctx context.Context, records []APIRecord) ([]APIRecord, []APIRecord, error) {
+	data, err := a.p.doRequest(ctx, opWrite, "PUT", "/records/bulk", bulkUpdateRecordsRequest{Records: records})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := bulkUpdateRecordsResponse{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, nil, err
+	}
+	return result.Records, result.FailedRecords, nil
+}
@@ -0,0 +1,131 @@
+package hetzner_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/libdns/hetzner"
+	"github.com/libdns/libdns"
+)
+
+// memoryAPI is a minimal hetzner.API holding the records of example.com.
+type memoryAPI struct {
+	mu      sync.Mutex
+	records []hetzner.APIRecord
+	nextID  int
+}
+
+func (api *memoryAPI) ListZones(ctx context.Context, name string, page int, perPage int) ([]hetzner.APIZone, hetzner.APIPagination, error) {
+	if name != "" && name != "example.com" {
+		return nil, hetzner.APIPagination{}, &hetzner.APIError{StatusCode: http.StatusNotFound}
+	}
+	return []hetzner.APIZone{{ID: "z", Name: "example.com", TTL: 86400}}, hetzner.APIPagination{Page: 1, LastPage: 1, TotalEntries: 1}, nil
+}
+
+func (api *memoryAPI) UpdateZone(ctx context.Context, z hetzner.APIZone) error {
+	return nil
+}
+
+func (api *memoryAPI) ListRecords(ctx context.Context, zoneID string, page int, perPage int) ([]hetzner.APIRecord, hetzner.APIPagination, error) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	return slices.Clone(api.records), hetzner.APIPagination{Page: 1, LastPage: 1, TotalEntries: len(api.records)}, nil
+}
+
+func (api *memoryAPI) GetRecord(ctx context.Context, id string) (hetzner.APIRecord, error) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	for _, r := range api.records {
+		if r.ID == id {
+			return r, nil
+		}
+	}
+	return hetzner.APIRecord{}, &hetzner.APIError{StatusCode: http.StatusNotFound}
+}
+
+func (api *memoryAPI) CreateRecord(ctx context.Context, r hetzner.APIRecord) (hetzner.APIRecord, error) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	api.nextID++
+	r.ID = fmt.Sprintf("r%d", api.nextID)
+	api.records = append(api.records, r)
+	return r, nil
+}
+
+func (api *memoryAPI) UpdateRecord(ctx context.Context, r hetzner.APIRecord) (hetzner.APIRecord, error) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	return api.updateRecord(r)
+}
+
+func (api *memoryAPI) updateRecord(r hetzner.APIRecord) (hetzner.APIRecord, error) {
+	for i := range api.records {
+		if api.records[i].ID == r.ID {
+			api.records[i] = r
+			return r, nil
+		}
+	}
+	return hetzner.APIRecord{}, &hetzner.APIError{StatusCode: http.StatusNotFound}
+}
+
+func (api *memoryAPI) DeleteRecord(ctx context.Context, id string) error {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	api.records = slices.DeleteFunc(api.records, func(r hetzner.APIRecord) bool { return r.ID == id })
+	return nil
+}
+
+func (api *memoryAPI) BulkUpdateRecords(ctx context.Context, records []hetzner.APIRecord) ([]hetzner.APIRecord, []hetzner.APIRecord, error) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	var updated, failed []hetzner.APIRecord
+	for _, r := range records {
+		if r, err := api.updateRecord(r); err == nil {
+			updated = append(updated, r)
+		} else {
+			failed = append(failed, r)
+		}
+	}
+	return updated, failed, nil
+}
+
+func Test_API(t *testing.T) {
+	api := &memoryAPI{}
+	p := &hetzner.Provider{API: api}
+
+	appended, err := p.AppendRecords(context.TODO(), "example.com.", []libdns.Record{
+		{Type: "A", Name: "www", Value: "192.0.2.1"},
+		{Type: "TXT", Name: "_acme-challenge", Value: "token"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.SetRecords(context.TODO(), "example.com.", []libdns.Record{{Type: "A", Name: "www", Value: "192.0.2.2"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.DeleteRecords(context.TODO(), "example.com.", appended[1:]); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := p.GetRecords(context.TODO(), "example.com.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Value != "192.0.2.2" || records[0].ID != appended[0].ID {
+		t.Fatalf("unexpected records => %v", records)
+	}
+
+	if _, err := p.GetRecords(context.TODO(), "example.org."); !hetzner.IsNotFound(err) {
+		t.Fatalf("err != not found => %v", err)
+	}
+}
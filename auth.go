@@ -0,0 +1,35 @@
+package hetzner
+
+import (
+	"strings"
+)
+
+// dnsTokenLength and cloudTokenLength are the lengths of Hetzner DNS Console
+// API tokens and of Hetzner Cloud API tokens.
+const (
+	dnsTokenLength   = 32
+	cloudTokenLength = 64
+)
+
+// tokenSettingsURL is where DNS Console API tokens are managed.
+const tokenSettingsURL = "https://dns.hetzner.com/settings/api-token"
+
+// forbiddenHint is the hint of 403 Forbidden errors, whose token is valid.
+const forbiddenHint = "the API token is valid but may not access this resource; check that the zone belongs to the token's account"
+
+// unauthorizedHint guesses why the API rejected the provider's token with
+// 401 Unauthorized, from the token alone. Missing tokens are caught by
+// Validate before.
+func (p *Provider) unauthorizedHint() string {
+	token := p.AuthAPIToken
+	switch {
+	case strings.ContainsFunc(token, func(r rune) bool { return !isLetterOrDigit(r) }):
+		return "the API token contains characters tokens never have, e.g. whitespace or quotes; check how it is read from the environment or config file"
+	case len(token) == cloudTokenLength:
+		return "the API token looks like a Hetzner Cloud API token, which the DNS Console API doesn't accept; create a DNS API token at " + tokenSettingsURL
+	case len(token) != dnsTokenLength:
+		return "the API token is not as long as DNS API tokens are; check that it was copied completely"
+	default:
+		return "the API token was revoked or is mistyped; check it at " + tokenSettingsURL
+	}
+}
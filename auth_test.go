@@ -0,0 +1,51 @@
+package hetzner_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/libdns/hetzner"
+)
+
+func Test_UnauthorizedHint(t *testing.T) {
+	testCases := []struct {
+		token string
+		hint  string
+	}{
+		{token: strings.Repeat("a", 64), hint: "Hetzner Cloud API token"},
+		{token: strings.Repeat("a", 20), hint: "copied completely"},
+		{token: `"` + strings.Repeat("a", 30) + `"`, hint: "characters tokens never have"},
+		{token: strings.Repeat("a", 32), hint: "revoked"},
+	}
+
+	for _, c := range testCases {
+		p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		})
+		p.AuthAPIToken = c.token
+
+		_, err := p.ListZones(context.TODO())
+		if err == nil || !strings.Contains(err.Error(), c.hint) {
+			t.Fatalf("%q: error without hint %q => %v", c.token, c.hint, err)
+		}
+	}
+}
+
+func Test_AuthErrors(t *testing.T) {
+	for status, target := range map[int]error{http.StatusUnauthorized: hetzner.ErrUnauthorized, http.StatusForbidden: hetzner.ErrForbidden} {
+		p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		})
+
+		_, err := p.GetRecords(context.TODO(), "example.com")
+		if !errors.Is(err, target) || !hetzner.IsAuthError(err) {
+			t.Fatalf("%d: err != %v => %v", status, target, err)
+		}
+		if status == http.StatusForbidden && !strings.Contains(err.Error(), "belongs to the token's account") {
+			t.Fatalf("403 error without hint => %v", err)
+		}
+	}
+}
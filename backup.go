@@ -0,0 +1,206 @@
+package hetzner
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BackupStore stores the zone backups made by a Backupper, e.g. in files or
+// an object store like S3.
+type BackupStore interface {
+	// Create returns a writer for a new backup of the zone with the given
+	// name. The backup is complete once the writer was closed without
+	// error.
+	Create(ctx context.Context, zone string, name string) (io.WriteCloser, error)
+
+	// List returns the names of the zone's backups, in any order.
+	List(ctx context.Context, zone string) ([]string, error)
+
+	// Remove deletes the zone's backup with the given name.
+	Remove(ctx context.Context, zone string, name string) error
+}
+
+// DirStore returns a BackupStore storing the backups of each zone as files
+// in a subdirectory of dir named after the zone.
+func DirStore(dir string) BackupStore {
+	return dirStore(dir)
+}
+
+type dirStore string
+
+func (d dirStore) Create(_ context.Context, zone string, name string) (io.WriteCloser, error) {
+	dir := filepath.Join(string(d), zone)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return os.Create(filepath.Join(dir, name))
+}
+
+func (d dirStore) List(_ context.Context, zone string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(string(d), zone))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+func (d dirStore) Remove(_ context.Context, zone string, name string) error {
+	return os.Remove(filepath.Join(string(d), zone, name))
+}
+
+// backupSuffix is the file name suffix of backups; names are the UTC time
+// the snapshot was taken, so they sort chronologically.
+const backupSuffix = ".json"
+
+// Backupper periodically backs up zones as JSON encoded snapshots, see
+// SnapshotZone, to a BackupStore.
+type Backupper struct {
+	Provider *Provider
+	Zones    []string
+	Store    BackupStore
+
+	// Interval is the time between two backups of the zones.
+	Interval time.Duration
+
+	// Keep is the number of backups kept per zone; older ones are removed
+	// after a new backup was stored. Zero keeps all backups.
+	Keep int
+
+	// ChangesOnly skips backups of zones whose records and settings didn't
+	// change since the last backup made by this Backupper.
+	ChangesOnly bool
+
+	// OnError, if set, is called with the errors of failed backups while
+	// Run keeps going.
+	OnError func(zone string, err error)
+
+	mu   sync.Mutex
+	last map[string][sha256.Size]byte
+}
+
+// Run backs up the zones immediately and then every Interval until ctx is
+// done, and returns the context's error.
+func (b *Backupper) Run(ctx context.Context) error {
+	if b.Interval <= 0 {
+		return fmt.Errorf("%w: Backupper.Interval must be positive", ErrInvalidConfig)
+	}
+
+	ticker := time.NewTicker(b.Interval)
+	defer ticker.Stop()
+
+	for {
+		b.BackupOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// BackupOnce backs up every zone once and returns the errors of the zones
+// that failed, joined.
+func (b *Backupper) BackupOnce(ctx context.Context) error {
+	var errs []error
+	for _, zone := range b.Zones {
+		if err := b.backup(ctx, unFQDN(zone)); err != nil {
+			if b.OnError != nil {
+				b.OnError(zone, err)
+			}
+			errs = append(errs, fmt.Errorf("backing up zone %s: %w", zone, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (b *Backupper) backup(ctx context.Context, zone string) error {
+	snapshot, err := b.Provider.SnapshotZone(ctx, zone)
+	if err != nil {
+		return err
+	}
+
+	// the time taken is excluded from the comparison with the last backup
+	content, err := json.Marshal(Snapshot{Zone: snapshot.Zone, TTL: snapshot.TTL, Records: snapshot.Records})
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(content)
+	b.mu.Lock()
+	unchanged := b.last[zone] == sum
+	b.mu.Unlock()
+	if b.ChangesOnly && unchanged {
+		return nil
+	}
+
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	encoder.SetIndent("", "\t")
+	if err := encoder.Encode(snapshot); err != nil {
+		return err
+	}
+
+	w, err := b.Store.Create(ctx, zone, snapshot.Taken.UTC().Format("20060102T150405.000Z")+backupSuffix)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data.Bytes()); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	if b.last == nil {
+		b.last = map[string][sha256.Size]byte{}
+	}
+	b.last[zone] = sum
+	b.mu.Unlock()
+
+	return b.rotate(ctx, zone)
+}
+
+// rotate removes all but the newest Keep backups of the zone.
+func (b *Backupper) rotate(ctx context.Context, zone string) error {
+	if b.Keep <= 0 {
+		return nil
+	}
+
+	names, err := b.Store.List(ctx, zone)
+	if err != nil {
+		return err
+	}
+	names = slices.DeleteFunc(names, func(name string) bool { return !strings.HasSuffix(name, backupSuffix) })
+	slices.Sort(names)
+
+	for len(names) > b.Keep {
+		if err := b.Store.Remove(ctx, zone, names[0]); err != nil {
+			return err
+		}
+		names = names[1:]
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package hetzner_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/libdns/hetzner"
+	"github.com/libdns/libdns"
+)
+
+func Test_Backupper(t *testing.T) {
+	p, _ := newFakeProvider(t, "A www 192.0.2.1")
+	dir := t.TempDir()
+
+	b := &hetzner.Backupper{
+		Provider:    p,
+		Zones:       []string{"example.com."},
+		Store:       hetzner.DirStore(dir),
+		Keep:        2,
+		ChangesOnly: true,
+	}
+
+	backups := func() int {
+		entries, err := os.ReadDir(filepath.Join(dir, "example.com"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(entries)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := b.BackupOnce(context.TODO()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := backups(); n != 1 {
+		t.Fatalf("backups != 1 => %d", n)
+	}
+
+	for i := 0; i < 3; i++ {
+		time.Sleep(2 * time.Millisecond)
+		if _, err := p.AppendRecords(context.TODO(), "example.com.", []libdns.Record{{Type: "TXT", Name: "change", Value: string(rune('a' + i))}}); err != nil {
+			t.Fatal(err)
+		}
+		if err := b.BackupOnce(context.TODO()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := backups(); n != 2 {
+		t.Fatalf("backups != 2 => %d", n)
+	}
+}
@@ -0,0 +1,157 @@
+package hetzner
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	"github.com/libdns/libdns"
+)
+
+// defaultConcurrency is the number of concurrent API requests batch
+// operations make when Provider.Concurrency is not set.
+const defaultConcurrency = 4
+
+func (p *Provider) concurrency() int {
+	if p.Concurrency > 0 {
+		return p.Concurrency
+	}
+	return defaultConcurrency
+}
+
+// BatchMode decides how batch operations like AppendRecords, SetRecords and
+// DeleteRecords handle failing records.
+type BatchMode int
+
+const (
+	// BatchBestEffort attempts every record and reports all failures
+	// together.
+	BatchBestEffort BatchMode = iota
+
+	// BatchFailFast stops starting new records after the first failure.
+	// Records that were skipped are reported with ErrSkipped.
+	BatchFailFast
+)
+
+// batchResult is the outcome of one record of a batch operation.
+type batchResult struct {
+	record libdns.Record
+	err    error
+	done   bool
+}
+
+// runBatch calls fn for every record with up to p.concurrency() calls in
+// flight. Records with the same name and type are handled one after another
+// in input order, so e.g. upserts into the same RRset don't race. In
+// BatchFailFast mode records that haven't been started when a call fails are
+// skipped. Once ctx is done, no further records are started either. The
+// results are in input order; skipped records are not done.
+func (p *Provider) runBatch(ctx context.Context, zone string, records []libdns.Record, fn func(libdns.Record) (libdns.Record, error)) []batchResult {
+	results := make([]batchResult, len(records))
+
+	var groups [][]int
+	groupOf := map[rrsetKey]int{}
+	for i, r := range records {
+		key := rrsetKey{name: normalizeRecordName(r.Name, zone), recordType: r.Type}
+		if len(r.ID) > 0 {
+			key = rrsetKey{name: "id:" + r.ID}
+		}
+		g, ok := groupOf[key]
+		if !ok {
+			g = len(groups)
+			groupOf[key] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], i)
+	}
+
+	var failed atomic.Bool
+	forEachConcurrently(len(groups), p.concurrency(), func(g int) {
+		for _, i := range groups[g] {
+			if ctx.Err() != nil || p.nearDeadline(ctx) || p.BatchMode == BatchFailFast && failed.Load() {
+				return
+			}
+			record, err := fn(records[i])
+			results[i] = batchResult{record: record, err: err, done: true}
+			if err != nil {
+				failed.Store(true)
+			}
+		}
+	})
+
+	return results
+}
+
+// batchError joins the errors of all failed and skipped records, each
+// wrapped in a RecordError, or returns nil if all records succeeded. Records
+// skipped because ctx is done, or its deadline is near, report ctx.Err() or
+// context.DeadlineExceeded, so the returned error matches context.Canceled
+// or context.DeadlineExceeded. Unless op is empty, it also holds an
+// *IncompleteError with a ResumeToken for the records that weren't processed
+// because of that.
+func (p *Provider) batchError(ctx context.Context, op string, zone string, records []libdns.Record, results []batchResult) error {
+	var errs []error
+	var remaining []libdns.Record
+	for i, result := range results {
+		if result.err != nil {
+			errs = append(errs, &RecordError{Record: records[i], Err: result.err})
+			if errors.Is(result.err, context.Canceled) || errors.Is(result.err, context.DeadlineExceeded) {
+				remaining = append(remaining, records[i])
+			}
+		}
+	}
+
+	skipErr := ErrSkipped
+	if ctx.Err() != nil {
+		skipErr = ctx.Err()
+	} else if p.nearDeadline(ctx) {
+		skipErr = context.DeadlineExceeded
+	} else if len(errs) == 0 {
+		return nil
+	}
+	for i, result := range results {
+		if !result.done {
+			errs = append(errs, &RecordError{Record: records[i], Err: skipErr})
+			if skipErr != ErrSkipped {
+				remaining = append(remaining, records[i])
+			}
+		}
+	}
+
+	if len(remaining) > 0 && op != "" {
+		errs = append(errs, &IncompleteError{Token: ResumeToken{Op: op, Zone: zone, Records: remaining}})
+	}
+	return errors.Join(errs...)
+}
+
+// dedupe returns records without the repetitions of records with the same
+// ID, name, type and value as an earlier one, which would create duplicate
+// records or claim the same record twice.
+func dedupe(zone string, records []libdns.Record) []libdns.Record {
+	type key struct {
+		id, name, recordType, value string
+	}
+
+	seen := make(map[key]bool, len(records))
+	unique := make([]libdns.Record, 0, len(records))
+	for _, r := range records {
+		k := key{r.ID, normalizeRecordName(r.Name, zone), r.Type, diffValue(r)}
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		unique = append(unique, r)
+	}
+	return unique
+}
+
+// succeeded returns the records of all successful results in input order.
+func succeeded(results []batchResult) []libdns.Record {
+	var records []libdns.Record
+	for _, result := range results {
+		if result.done && result.err == nil {
+			records = append(records, result.record)
+		}
+	}
+	return records
+}
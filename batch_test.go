@@ -0,0 +1,273 @@
+package hetzner_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/libdns/hetzner"
+	"github.com/libdns/libdns"
+)
+
+// batchHandler serves a zone "example.com" and creates records, failing
+// those whose value starts with "bad".
+func batchHandler(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/zones":
+		fmt.Fprint(w, `{"zones":[{"id":"z","name":"example.com"}]}`)
+	case r.Method == http.MethodPost && r.URL.Path == "/records":
+		var record struct {
+			Type  string `json:"type"`
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		}
+		json.NewDecoder(r.Body).Decode(&record)
+		if strings.HasPrefix(record.Value, "bad") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintf(w, `{"record":{"id":"id-%s","type":"%s","name":"%s","value":"%s"}}`, record.Value, record.Type, record.Name, record.Value)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func Test_AppendRecordsAggregatesErrors(t *testing.T) {
+	p := newTestProvider(t, batchHandler)
+
+	records, err := p.AppendRecords(context.TODO(), "example.com", []libdns.Record{
+		{Type: "TXT", Name: "a", Value: "bad1"},
+		{Type: "TXT", Name: "b", Value: "good"},
+		{Type: "TXT", Name: "c", Value: "bad2"},
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	var recordErr *hetzner.RecordError
+	if !errors.As(err, &recordErr) {
+		t.Fatalf("expected RecordError, got %v", err)
+	}
+	for _, s := range []string{`"a" with value "bad1"`, `"c" with value "bad2"`} {
+		if !strings.Contains(err.Error(), s) {
+			t.Fatalf("error doesn't mention %s: %v", s, err)
+		}
+	}
+	if strings.Contains(err.Error(), "good") {
+		t.Fatalf("error mentions successful record: %v", err)
+	}
+
+	if len(records) != 1 || records[0].ID != "id-good" {
+		t.Fatalf("expected only the created record, got %+v", records)
+	}
+}
+
+func Test_AppendRecordsFailFast(t *testing.T) {
+	p := newTestProvider(t, batchHandler)
+	p.BatchMode = hetzner.BatchFailFast
+	p.Concurrency = 1
+
+	records, err := p.AppendRecords(context.TODO(), "example.com", []libdns.Record{
+		{Type: "TXT", Name: "a", Value: "good1"},
+		{Type: "TXT", Name: "b", Value: "bad"},
+		{Type: "TXT", Name: "c", Value: "good2"},
+	})
+	if !errors.Is(err, hetzner.ErrSkipped) {
+		t.Fatalf("expected ErrSkipped, got %v", err)
+	}
+	if len(records) != 1 || records[0].ID != "id-good1" {
+		t.Fatalf("expected only the first record, got %+v", records)
+	}
+}
+
+func Test_AppendRecordsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p := newTestProvider(t, batchHandler)
+	p.Concurrency = 1
+	p.OutgoingRecordHook = func(zone string, r libdns.Record) libdns.Record {
+		if r.Name == "b" {
+			cancel()
+		}
+		return r
+	}
+
+	records, err := p.AppendRecords(ctx, "example.com", []libdns.Record{
+		{Type: "TXT", Name: "a", Value: "good1"},
+		{Type: "TXT", Name: "b", Value: "good2"},
+		{Type: "TXT", Name: "c", Value: "good3"},
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if len(records) != 1 || records[0].ID != "id-good1" {
+		t.Fatalf("expected only the first record, got %+v", records)
+	}
+}
+
+func Test_ResumeToken(t *testing.T) {
+	p, api := newFakeProvider(t, "A www 192.0.2.1")
+	p.BatchDeadlineMargin = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+	records := []libdns.Record{{Type: "A", Name: "www", Value: "192.0.2.2"}, {Type: "A", Name: "api", Value: "192.0.2.3"}}
+	_, err := p.SetRecords(ctx, "example.com", records)
+
+	var incomplete *hetzner.IncompleteError
+	if !errors.As(err, &incomplete) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err is not an IncompleteError => %v", err)
+	}
+	if len(incomplete.Token.Records) != 2 {
+		t.Fatalf("unexpected resume token => %+v", incomplete.Token)
+	}
+
+	token, err := hetzner.ParseResumeToken(incomplete.Token.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Resume(context.Background(), token); err != nil {
+		t.Fatal(err)
+	}
+	if records := api.dump("z"); len(records) != 2 || records[0] != "A www 192.0.2.2" || records[1] != "A api 192.0.2.3" {
+		t.Fatalf("unexpected records => %v", records)
+	}
+}
+
+func Test_BatchDedupe(t *testing.T) {
+	p, api := newFakeProvider(t)
+
+	records, err := p.AppendRecords(context.TODO(), "example.com", []libdns.Record{
+		{Type: "A", Name: "www", Value: "192.0.2.1"},
+		{Type: "A", Name: "www.example.com.", Value: "192.0.2.1"},
+		{Type: "A", Name: "www", Value: "192.0.2.2"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("len(records) != 2 => %v", records)
+	}
+
+	if _, err := p.SetRecords(context.TODO(), "example.com", []libdns.Record{
+		{Type: "TXT", Name: "@", Value: "v=spf1 -all"},
+		{Type: "TXT", Name: "@", Value: "v=spf1 -all"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if records := api.dump("z"); len(records) != 3 {
+		t.Fatalf("unexpected records => %v", records)
+	}
+}
+
+func Test_DeleteRecordsWithoutID(t *testing.T) {
+	api := &fakeAPI{zones: []fakeZone{{ID: "z", Name: "example.com", TTL: 86400}}}
+	for _, r := range []string{"A www 192.0.2.1", "A www 192.0.2.2", "TXT @ v=spf1 -all", "MX @ 10 mail.example.com."} {
+		api.addRecord("z", r)
+	}
+	var listings atomic.Int32
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && r.URL.Path == "/records" {
+			listings.Add(1)
+		}
+		api.ServeHTTP(w, r)
+	})
+
+	deleted, err := p.DeleteRecords(context.TODO(), "example.com", []libdns.Record{
+		{Type: "A", Name: "www", Value: "192.0.2.2"},
+		{Type: "TXT", Name: "@", Value: "v=spf1 -all"},
+		{Type: "MX", Name: "@", Value: "10 MAIL.example.com"},
+		{Type: "A", Name: "www", Value: "192.0.2.9"},
+	})
+	if !errors.Is(err, hetzner.ErrRecordNotFound) {
+		t.Fatalf("err != ErrRecordNotFound => %v", err)
+	}
+	if len(deleted) != 3 || listings.Load() != 1 {
+		t.Fatalf("unexpected deletes => %v, %d listings", deleted, listings.Load())
+	}
+	if records := api.dump("z"); len(records) != 1 || records[0] != "A www 192.0.2.1" {
+		t.Fatalf("unexpected records => %v", records)
+	}
+}
+
+func Test_SyncRecordsBatch(t *testing.T) {
+	api := &fakeAPI{zones: []fakeZone{{ID: "z", Name: "example.com", TTL: 86400}}}
+	api.addRecord("z", "TXT old old")
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/records" {
+			var record fakeRecord
+			json.NewDecoder(r.Body).Decode(&record)
+			if strings.HasPrefix(record.Value, "bad") {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			api.mu.Lock()
+			defer api.mu.Unlock()
+			json.NewEncoder(w).Encode(map[string]interface{}{"record": api.add(record)})
+			return
+		}
+		api.ServeHTTP(w, r)
+	})
+
+	// every create is attempted, but nothing is deleted after a failure
+	_, err := p.SyncRecords(context.TODO(), "example.com", []libdns.Record{
+		{Type: "TXT", Name: "a", Value: "bad"},
+		{Type: "TXT", Name: "b", Value: "b"},
+	})
+	var recordErr *hetzner.RecordError
+	if !errors.As(err, &recordErr) || !errors.Is(err, hetzner.ErrSkipped) {
+		t.Fatalf("unexpected error => %v", err)
+	}
+	if records := api.dump("z"); len(records) != 2 || records[0] != "TXT old old" || records[1] != "TXT b b" {
+		t.Fatalf("unexpected records => %v", records)
+	}
+
+	p.BatchMode = hetzner.BatchFailFast
+	p.Concurrency = 1
+	_, err = p.SyncRecords(context.TODO(), "example.com", []libdns.Record{
+		{Type: "TXT", Name: "a", Value: "bad"},
+		{Type: "TXT", Name: "c", Value: "c"},
+	})
+	if !errors.Is(err, hetzner.ErrSkipped) {
+		t.Fatalf("err != ErrSkipped => %v", err)
+	}
+	if records := api.dump("z"); len(records) != 2 {
+		t.Fatalf("records were changed after the first failure => %v", records)
+	}
+}
+
+func Test_SetRecordsKeepsTTL(t *testing.T) {
+	p, api := newFakeProvider(t)
+	ttl := 600
+	api.mu.Lock()
+	api.add(fakeRecord{ZoneID: "z", Type: "TXT", Name: "test", Value: "old", TTL: &ttl})
+	api.mu.Unlock()
+
+	updates := []libdns.Record{
+		{ID: "r1", Value: "new"},
+		{ID: "r1", Type: "TXT", Name: "test", Value: "newer"},
+	}
+	for _, update := range updates {
+		records, err := p.SetRecords(context.TODO(), "example.com", []libdns.Record{update})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != 1 || records[0].Value != update.Value || records[0].TTL != 10*time.Minute {
+			t.Fatalf("unexpected records => %+v", records)
+		}
+
+		api.mu.Lock()
+		stored := api.records[0]
+		api.mu.Unlock()
+		if stored.Value != update.Value || stored.TTL == nil || *stored.TTL != ttl {
+			t.Fatalf("stored TTL not kept => %+v", stored)
+		}
+	}
+}
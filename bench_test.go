@@ -0,0 +1,82 @@
+package hetzner_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/libdns/hetzner"
+	"github.com/libdns/libdns"
+)
+
+// The benchmarks run against a local fake API, e.g. with
+//
+//	go test -run '^$' -bench . -cpuprofile cpu.out
+
+// benchZoneSize is the number of records in the zone the benchmarks use.
+const benchZoneSize = 50000
+
+// newBenchProvider returns a provider for a fake zone "example.com" with
+// benchZoneSize TXT records named r0, r1, ... that accepts all updates.
+func newBenchProvider(b *testing.B) *hetzner.Provider {
+	b.Helper()
+
+	var records bytes.Buffer
+	records.WriteString(`{"records":[`)
+	for i := 0; i < benchZoneSize; i++ {
+		if i > 0 {
+			records.WriteByte(',')
+		}
+		fmt.Fprintf(&records, `{"id":"%d","zone_id":"z","type":"TXT","name":"r%d","value":"v%d","ttl":300}`, i, i, i)
+	}
+	records.WriteString(`]}`)
+
+	server := func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/zones":
+			fmt.Fprint(w, `{"zones":[{"id":"z","name":"example.com"}]}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/records":
+			w.Write(records.Bytes())
+		case r.Method == http.MethodGet:
+			fmt.Fprint(w, `{"record":{"id":"1","zone_id":"z","type":"TXT","name":"r1","value":"v1","ttl":300}}`)
+		default:
+			fmt.Fprint(w, `{"record":{"id":"1","zone_id":"z","type":"TXT","name":"r1","value":"new","ttl":300}}`)
+		}
+	}
+
+	return newTestProvider(b, server)
+}
+
+func BenchmarkGetRecords(b *testing.B) {
+	p := newBenchProvider(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		records, err := p.GetRecords(context.TODO(), "example.com.")
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(records) != benchZoneSize {
+			b.Fatalf("len(records) != %d => %d", benchZoneSize, len(records))
+		}
+	}
+}
+
+func BenchmarkSetRecords(b *testing.B) {
+	p := newBenchProvider(b)
+	updates := make([]libdns.Record, 1000)
+	for i := range updates {
+		updates[i] = libdns.Record{Type: "TXT", Name: fmt.Sprintf("r%d", i*50), Value: "new"}
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := p.SetRecords(context.TODO(), "example.com.", updates); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
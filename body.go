@@ -0,0 +1,69 @@
+package hetzner
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// bufferPool holds buffers for JSON request bodies, so batch operations
+// don't allocate a new one for every request.
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// requestBody is a JSON request body in a pooled buffer. The buffer is
+// returned to the pool once the body was released by doRequest and closed by
+// the transport for every attempt, as the transport may still read the body
+// after the response was returned.
+type requestBody struct {
+	buf  *bytes.Buffer
+	refs atomic.Int32
+}
+
+// newRequestBody encodes payload as JSON. The caller must call release
+// when done with the body.
+func newRequestBody(payload interface{}) (*requestBody, error) {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	if err := json.NewEncoder(buf).Encode(payload); err != nil {
+		bufferPool.Put(buf)
+		return nil, err
+	}
+
+	b := &requestBody{buf: buf}
+	b.refs.Store(1)
+	return b, nil
+}
+
+// len returns the length of the body in bytes.
+func (b *requestBody) len() int64 {
+	return int64(b.buf.Len())
+}
+
+// reader returns a new reader of the body, which must be closed.
+func (b *requestBody) reader() io.ReadCloser {
+	b.refs.Add(1)
+	return &bodyReader{Reader: bytes.NewReader(b.buf.Bytes()), body: b}
+}
+
+func (b *requestBody) release() {
+	if b.refs.Add(-1) == 0 {
+		bufferPool.Put(b.buf)
+	}
+}
+
+type bodyReader struct {
+	*bytes.Reader
+	body   *requestBody
+	closed atomic.Bool
+}
+
+func (r *bodyReader) Close() error {
+	if r.closed.CompareAndSwap(false, true) {
+		r.body.release()
+	}
+	return nil
+}
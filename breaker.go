@@ -0,0 +1,138 @@
+package hetzner
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// defaultBreakerCooldown is used when Provider.BreakerCooldown is not set.
+const defaultBreakerCooldown = 30 * time.Second
+
+// BreakerState is the state of the provider's circuit breaker.
+type BreakerState int
+
+const (
+	// BreakerClosed lets requests through.
+	BreakerClosed BreakerState = iota
+
+	// BreakerOpen fails requests immediately with ErrCircuitOpen until the
+	// cool-down period has passed.
+	BreakerOpen
+
+	// BreakerHalfOpen lets a single trial request through after the
+	// cool-down. Its outcome closes or re-opens the breaker.
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// circuitBreaker counts consecutive failed requests. It is shared by all
+// goroutines using the same Provider.
+type circuitBreaker struct {
+	mu       sync.Mutex
+	failures int
+	state    BreakerState
+	openedAt time.Time
+	trial    bool
+}
+
+// BreakerState returns the current state of the circuit breaker, so callers
+// can skip their own retries while the API is considered unavailable.
+func (p *Provider) BreakerState() BreakerState {
+	b := &p.breaker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerOpen && time.Since(b.openedAt) >= p.breakerCooldown() {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
+
+// allowRequest returns ErrCircuitOpen if the breaker is open.
+func (p *Provider) allowRequest() error {
+	if p.BreakerThreshold <= 0 {
+		return nil
+	}
+
+	b := &p.breaker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < p.breakerCooldown() {
+			return ErrCircuitOpen
+		}
+		b.state = BreakerHalfOpen
+		b.trial = true
+		return nil
+	case BreakerHalfOpen:
+		if b.trial {
+			return ErrCircuitOpen
+		}
+		b.trial = true
+	}
+	return nil
+}
+
+// recordResult updates the breaker with the outcome of a request. Only
+// network errors (including per-operation timeouts), 5xx and 429 responses
+// count as failures. Requests ended by the caller's context say nothing
+// about the API and neither count as failure nor as success; they only
+// free the trial slot of a half-open breaker.
+func (p *Provider) recordResult(ctx context.Context, err error) {
+	if p.BreakerThreshold <= 0 {
+		return
+	}
+
+	neutral := err != nil && (ctx.Err() != nil || errors.Is(err, context.Canceled))
+	failed := err != nil
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		neutral = false
+		failed = apiErr.StatusCode >= 500 || apiErr.StatusCode == http.StatusTooManyRequests
+	}
+
+	b := &p.breaker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+	if neutral {
+		return
+	}
+	if !failed {
+		b.failures = 0
+		b.state = BreakerClosed
+		return
+	}
+
+	b.failures++
+	if b.state == BreakerHalfOpen || b.failures >= p.BreakerThreshold {
+		if b.state != BreakerOpen {
+			p.logf("circuit breaker opened after %d consecutive failures", b.failures)
+		}
+		b.state = BreakerOpen
+		b.openedAt = time.Now()
+	}
+}
+
+func (p *Provider) breakerCooldown() time.Duration {
+	if p.BreakerCooldown > 0 {
+		return p.BreakerCooldown
+	}
+	return defaultBreakerCooldown
+}
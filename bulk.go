@@ -0,0 +1,138 @@
+package hetzner
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+type bulkUpdateRecordsRequest struct {
+	Records []APIRecord `json:"records"`
+}
+
+type bulkUpdateRecordsResponse struct {
+	Records       []APIRecord `json:"records"`
+	FailedRecords []APIRecord `json:"failed_records"`
+}
+
+// SetZoneRecordTTLs sets the TTL of all records in the zone for which filter
+// returns true, or of all records if filter is nil, e.g. to lower TTLs ahead
+// of a migration. A TTL of InheritZoneTTL makes the records follow the zone's
+// default TTL. The records are updated through the bulk update endpoint in
+// requests of up to Provider.BulkChunkSize records, up to
+// Provider.Concurrency at a time; records that already have the TTL and the
+// SOA record are left alone. It returns the updated records.
+//
+// The update is not atomic. If Hetzner rejects some of the records or a
+// request fails, the records of the other requests are updated nonetheless,
+// and the returned error joins a RecordError for each rejected record and
+// each record of a failed request. Calling it again retries the records left
+// with the old TTL.
+func (p *Provider) SetZoneRecordTTLs(ctx context.Context, zone string, ttl time.Duration, filter func(libdns.Record) bool) ([]libdns.Record, error) {
+	zone = unFQDN(zone)
+
+	records, err := p.getAllRecords(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []libdns.Record
+	for _, r := range records {
+		if r.Type == "SOA" || sameTTL(ttl, r.TTL) || (filter != nil && !filter(r)) {
+			continue
+		}
+		r.TTL = ttl
+		changed = append(changed, r)
+	}
+	if len(changed) == 0 {
+		return nil, nil
+	}
+
+	return p.bulkUpdateRecords(ctx, zone, changed)
+}
+
+// defaultBulkChunkSize is used when Provider.BulkChunkSize is not set.
+const defaultBulkChunkSize = 100
+
+func (p *Provider) bulkChunkSize() int {
+	if p.BulkChunkSize > 0 {
+		return p.BulkChunkSize
+	}
+	return defaultBulkChunkSize
+}
+
+// bulkUpdateRecords updates the records, which must have IDs, with requests
+// of up to Provider.BulkChunkSize records each, up to Provider.Concurrency at
+// a time. All records are checked before the first request. It returns the
+// updated records in input order and a RecordError for each record the API
+// rejected or whose chunk failed.
+func (p *Provider) bulkUpdateRecords(ctx context.Context, zone string, records []libdns.Record) ([]libdns.Record, error) {
+	zoneID, err := p.getZoneID(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	reg, err := p.registry(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	apiRecords := make([]APIRecord, 0, len(records))
+	for _, r := range records {
+		if err := reg.check(ctx, r); err != nil {
+			return nil, &RecordError{Record: r, Err: err}
+		}
+		out, err := p.outgoingRecord(ctx, zone, r)
+		if err != nil {
+			return nil, &RecordError{Record: r, Err: err}
+		}
+		ttl, err := p.apiTTL(out)
+		if err != nil {
+			return nil, &RecordError{Record: r, Err: err}
+		}
+		apiRecords = append(apiRecords, APIRecord{
+			ID:     out.ID,
+			ZoneID: zoneID,
+			Type:   out.Type,
+			Name:   out.Name,
+			Value:  out.Value,
+			TTL:    ttl,
+		})
+	}
+
+	chunks := slices.Collect(slices.Chunk(apiRecords, p.bulkChunkSize()))
+	updated := make([][]libdns.Record, len(chunks))
+	errs := make([][]error, len(chunks))
+	forEachConcurrently(len(chunks), p.concurrency(), func(i int) {
+		updated[i], errs[i] = p.bulkUpdateChunk(ctx, zone, chunks[i], records[i*p.bulkChunkSize():])
+	})
+
+	return slices.Concat(updated...), errors.Join(slices.Concat(errs...)...)
+}
+
+// bulkUpdateChunk sends one request of bulkUpdateRecords. records are the
+// records, as given to bulkUpdateRecords, starting with the chunk's first.
+func (p *Provider) bulkUpdateChunk(ctx context.Context, zone string, chunk []APIRecord, records []libdns.Record) ([]libdns.Record, []error) {
+	result, failed, err := p.api().BulkUpdateRecords(ctx, chunk)
+	if err != nil {
+		errs := make([]error, 0, len(chunk))
+		for _, r := range records[:len(chunk)] {
+			errs = append(errs, &RecordError{Record: r, Err: err})
+		}
+		return nil, errs
+	}
+
+	updated := make([]libdns.Record, 0, len(result))
+	for _, r := range result {
+		updated = append(updated, p.incomingRecord(zone, r))
+	}
+
+	var errs []error
+	for _, r := range failed {
+		errs = append(errs, &RecordError{Record: p.incomingRecord(zone, r), Err: errors.New("rejected by the bulk update endpoint")})
+	}
+	return updated, errs
+}
@@ -0,0 +1,77 @@
+package hetzner_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/libdns/hetzner"
+	"github.com/libdns/libdns"
+)
+
+func Test_SetZoneRecordTTLs(t *testing.T) {
+	p, _ := newFakeProvider(t, "A www 192.0.2.1", "A mail 192.0.2.2", "TXT @ v=spf1 -all")
+
+	onlyA := func(r libdns.Record) bool { return r.Type == "A" }
+	updated, err := p.SetZoneRecordTTLs(context.TODO(), "example.com", 5*time.Minute, onlyA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updated) != 2 {
+		t.Fatalf("len(updated) != 2 => %d", len(updated))
+	}
+
+	records, err := p.GetRecords(context.TODO(), "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range records {
+		var want time.Duration
+		if r.Type == "A" {
+			want = 5 * time.Minute
+		}
+		if r.TTL != want {
+			t.Fatalf("%s record %q has TTL %s != %s", r.Type, r.Name, r.TTL, want)
+		}
+	}
+
+	updated, err = p.SetZoneRecordTTLs(context.TODO(), "example.com", 5*time.Minute, onlyA)
+	if err != nil || len(updated) != 0 {
+		t.Fatalf("unchanged records were updated => %v, %v", updated, err)
+	}
+}
+
+func Test_BulkChunking(t *testing.T) {
+	var hosts []string
+	for i := 0; i < 25; i++ {
+		hosts = append(hosts, fmt.Sprintf("TXT host%d value%d", i, i))
+	}
+	_, api := newFakeProvider(t, hosts...)
+	api.records[12].Value = "bad value"
+
+	var requests atomic.Int32
+	handler := api.ServeHTTP
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/records/bulk" {
+			requests.Add(1)
+		}
+		handler(w, r)
+	})
+	p.BulkChunkSize = 10
+
+	updated, err := p.SetZoneRecordTTLs(context.TODO(), "example.com", time.Hour, nil)
+	if n := requests.Load(); n != 3 {
+		t.Fatalf("bulk requests != 3 => %d", n)
+	}
+	if len(updated) != 24 {
+		t.Fatalf("len(updated) != 24 => %d", len(updated))
+	}
+	var recordErr *hetzner.RecordError
+	if !errors.As(err, &recordErr) || recordErr.Record.Name != "host12" {
+		t.Fatalf("unexpected error => %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package hetzner
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// CAA property tags defined by RFC 8659.
+const (
+	CAATagIssue     = "issue"
+	CAATagIssueWild = "issuewild"
+	CAATagIODEF     = "iodef"
+)
+
+// CAA is the value of a CAA record, e.g. 0 issue "letsencrypt.org".
+type CAA struct {
+	// Flags are the CAA flags; 128 marks the property as critical.
+	Flags uint8
+
+	// Tag is the property tag, e.g. CAATagIssue.
+	Tag string
+
+	// Value is the property value without quotes, e.g. "letsencrypt.org" or
+	// "mailto:security@example.com".
+	Value string
+}
+
+// String formats c as a CAA record value as expected by the Hetzner API,
+// with the value in double quotes.
+func (c CAA) String() string {
+	value := strings.ReplaceAll(c.Value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return fmt.Sprintf(`%d %s "%s"`, c.Flags, c.Tag, value)
+}
+
+// Record returns a CAA record with c as its value.
+func (c CAA) Record(name string, ttl time.Duration) libdns.Record {
+	return libdns.Record{
+		Type:  "CAA",
+		Name:  name,
+		Value: c.String(),
+		TTL:   ttl,
+	}
+}
+
+// ParseCAA parses a CAA record value like 0 issue "letsencrypt.org". The
+// property value may be unquoted.
+func ParseCAA(value string) (CAA, error) {
+	fields := strings.SplitN(strings.TrimSpace(value), " ", 3)
+	if len(fields) != 3 {
+		return CAA{}, fmt.Errorf("%q is not of the form \"<flags> <tag> <value>\"", value)
+	}
+
+	flags, err := strconv.ParseUint(fields[0], 10, 8)
+	if err != nil {
+		return CAA{}, fmt.Errorf("CAA flags %q are not a number between 0 and 255", fields[0])
+	}
+
+	tag := fields[1]
+	if tag == "" {
+		return CAA{}, errors.New("CAA tag is empty")
+	}
+	for _, c := range tag {
+		if !isLetterOrDigit(c) {
+			return CAA{}, fmt.Errorf("CAA tag %q must consist of letters and digits", tag)
+		}
+	}
+
+	property := strings.TrimSpace(fields[2])
+	if len(property) >= 2 && strings.HasPrefix(property, `"`) && strings.HasSuffix(property, `"`) {
+		property = property[1 : len(property)-1]
+		property = strings.ReplaceAll(property, `\"`, `"`)
+		property = strings.ReplaceAll(property, `\\`, `\`)
+	} else if strings.ContainsRune(property, '"') {
+		return CAA{}, fmt.Errorf("CAA value %q is not properly quoted", fields[2])
+	}
+
+	return CAA{Flags: uint8(flags), Tag: tag, Value: property}, nil
+}
@@ -0,0 +1,38 @@
+package hetzner_test
+
+import (
+	"testing"
+
+	"github.com/libdns/hetzner"
+)
+
+func Test_CAA(t *testing.T) {
+	caa := hetzner.CAA{Flags: 128, Tag: hetzner.CAATagIssueWild, Value: `ca.example.net; account="1"`}
+
+	value := caa.String()
+	if value != `128 issuewild "ca.example.net; account=\"1\""` {
+		t.Fatalf("unexpected value => %s", value)
+	}
+
+	parsed, err := hetzner.ParseCAA(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed != caa {
+		t.Fatalf("%+v != %+v", parsed, caa)
+	}
+
+	parsed, err = hetzner.ParseCAA("0 iodef mailto:security@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Tag != hetzner.CAATagIODEF || parsed.Value != "mailto:security@example.com" {
+		t.Fatalf("unexpected CAA => %+v", parsed)
+	}
+
+	for _, invalid := range []string{"issue letsencrypt.org", `0 is-sue "letsencrypt.org"`, `300 issue "x"`, `0 issue "x`} {
+		if _, err := hetzner.ParseCAA(invalid); err == nil {
+			t.Fatalf("ParseCAA(%q) succeeded", invalid)
+		}
+	}
+}
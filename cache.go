@@ -0,0 +1,181 @@
+package hetzner
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache is a key-value store with expiring entries the provider caches zone
+// lookups in. The default is an in-memory cache; deployments with several
+// replicas can share lookups by setting Provider.Cache to an implementation
+// backed by e.g. Redis. Implementations must be safe for concurrent use and
+// should treat backend errors as cache misses.
+type Cache interface {
+	// Get returns the value stored for key and whether there is one that
+	// hasn't expired.
+	Get(ctx context.Context, key string) ([]byte, bool)
+
+	// Set stores value for key for ttl.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration)
+
+	// Delete removes the value stored for key, if any.
+	Delete(ctx context.Context, key string)
+}
+
+// NewMemoryCache returns the in-memory Cache the provider uses by default.
+func NewMemoryCache() Cache {
+	return &memoryCache{entries: map[string]memoryCacheEntry{}}
+}
+
+type memoryCache struct {
+	mu      sync.Mutex
+	entries map[string]memoryCacheEntry
+}
+
+type memoryCacheEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+func (c *memoryCache) Get(_ context.Context, key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (c *memoryCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = memoryCacheEntry{value: value, expires: time.Now().Add(ttl)}
+}
+
+func (c *memoryCache) Delete(_ context.Context, key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, key)
+}
+
+// cache returns Provider.Cache or, if it isn't set, the default in-memory
+// cache.
+func (p *Provider) cache() Cache {
+	if p.Cache != nil {
+		return p.Cache
+	}
+
+	p.memoryCacheOnce.Do(func() {
+		p.memoryCache = NewMemoryCache()
+	})
+	return p.memoryCache
+}
+
+// zoneCache caches zone IDs by zone name in the provider's Cache, under keys
+// with the given prefix. It remembers the zones it stored, so they can be
+// flushed from caches that can't list their keys.
+type zoneCache struct {
+	prefix  string
+	ttl     time.Duration
+	backend func() Cache
+
+	mu    sync.Mutex
+	zones map[string]struct{}
+}
+
+func newZoneCache(prefix string, ttl time.Duration, backend func() Cache) *zoneCache {
+	return &zoneCache{prefix: "libdns-hetzner:" + prefix, ttl: ttl, backend: backend, zones: map[string]struct{}{}}
+}
+
+func (c *zoneCache) get(ctx context.Context, zone string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+
+	id, ok := c.backend().Get(ctx, c.prefix+zone)
+	return string(id), ok
+}
+
+func (c *zoneCache) set(ctx context.Context, zone string, id string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	c.zones[zone] = struct{}{}
+	c.mu.Unlock()
+
+	c.backend().Set(ctx, c.prefix+zone, []byte(id), c.ttl)
+}
+
+func (c *zoneCache) delete(ctx context.Context, zone string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	delete(c.zones, zone)
+	c.mu.Unlock()
+
+	c.backend().Delete(ctx, c.prefix+zone)
+}
+
+// clear deletes all zones stored by this provider.
+func (c *zoneCache) clear(ctx context.Context) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	zones := c.zones
+	c.zones = map[string]struct{}{}
+	c.mu.Unlock()
+
+	for zone := range zones {
+		c.backend().Delete(ctx, c.prefix+zone)
+	}
+}
+
+// ForgetMissingZone clears the negative cache entry of a zone remembered as
+// missing with WithNegativeCache, e.g. after the zone was created.
+func (p *Provider) ForgetMissingZone(zone string) {
+	p.missingZones.delete(context.Background(), unFQDN(zone))
+}
+
+// FlushCache drops everything the provider has cached about the zone: its
+// zone ID, a negative cache entry and remembered responses for conditional
+// requests. Use it to force fresh state after changing the zone outside of
+// the provider, e.g. in the Hetzner DNS Console.
+func (p *Provider) FlushCache(zone string) {
+	ctx := context.Background()
+	zone = unFQDN(zone)
+
+	id, hasID := p.zoneIDs.get(ctx, zone)
+	p.zoneIDs.delete(ctx, zone)
+	p.missingZones.delete(ctx, zone)
+
+	p.conditional.deleteFunc(func(path string) bool {
+		if path == "/zones?name="+url.QueryEscape(zone) || strings.HasPrefix(path, "/zones?page=") {
+			return true
+		}
+		records := "/records?zone_id=" + url.QueryEscape(id)
+		return hasID && (path == records || strings.HasPrefix(path, records+"&"))
+	})
+}
+
+// FlushAll drops everything the provider has cached, for all zones. Of a
+// shared Cache, only the entries this provider stored are deleted.
+func (p *Provider) FlushAll() {
+	ctx := context.Background()
+	p.zoneIDs.clear(ctx)
+	p.missingZones.clear(ctx)
+	p.conditional.deleteFunc(func(string) bool { return true })
+}
@@ -0,0 +1,90 @@
+package hetzner
+
+import (
+	"context"
+	"maps"
+	"time"
+)
+
+// callOptions adjust the behavior of the provider for a single call. They
+// are carried by the context passed to the call.
+type callOptions struct {
+	skipCache bool
+	noRetry   bool
+	timeout   time.Duration
+	force     bool
+	requestID string
+
+	// unrestricted skips Provider.Scope and Provider.Policy, for the
+	// ownership registry records the provider maintains itself.
+	unrestricted bool
+
+	// zoneIDs maps zone names to IDs known to the caller.
+	zoneIDs map[string]string
+}
+
+type callOptionsKey struct{}
+
+func optionsFrom(ctx context.Context) callOptions {
+	opts, _ := ctx.Value(callOptionsKey{}).(callOptions)
+	return opts
+}
+
+func withOptions(ctx context.Context, adjust func(*callOptions)) context.Context {
+	opts := optionsFrom(ctx)
+	adjust(&opts)
+	return context.WithValue(ctx, callOptionsKey{}, opts)
+}
+
+// WithSkipCache returns a context making calls with it look up zone IDs from
+// the API even if they are cached. The fresh IDs are still cached for later
+// calls.
+func WithSkipCache(ctx context.Context) context.Context {
+	return withOptions(ctx, func(opts *callOptions) { opts.skipCache = true })
+}
+
+// WithNoRetry returns a context making calls with it send every request only
+// once, regardless of Provider.Retry.
+func WithNoRetry(ctx context.Context) context.Context {
+	return withOptions(ctx, func(opts *callOptions) { opts.noRetry = true })
+}
+
+// WithTimeout returns a context making calls with it bound each individual
+// API request by timeout, instead of the provider's configured timeouts.
+// Unlike context.WithTimeout, it doesn't bound the call as a whole, e.g.
+// including retries.
+func WithTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return withOptions(ctx, func(opts *callOptions) { opts.timeout = timeout })
+}
+
+// WithForce returns a context making calls with it change and delete
+// records regardless of their owner, see Provider.OwnerID.
+func WithForce(ctx context.Context) context.Context {
+	return withOptions(ctx, func(opts *callOptions) { opts.force = true })
+}
+
+// unrestricted returns a context making calls with it ignore Provider.Scope
+// and Provider.Policy.
+func unrestricted(ctx context.Context) context.Context {
+	return withOptions(ctx, func(opts *callOptions) { opts.unrestricted = true })
+}
+
+// WithZoneID returns a context making calls with it use id as the ID of the
+// zone instead of looking it up, e.g. the RecordInfo.ZoneID of a record
+// fetched by an earlier process.
+func WithZoneID(ctx context.Context, zone string, id string) context.Context {
+	return withOptions(ctx, func(opts *callOptions) {
+		opts.zoneIDs = maps.Clone(opts.zoneIDs)
+		if opts.zoneIDs == nil {
+			opts.zoneIDs = map[string]string{}
+		}
+		opts.zoneIDs[unFQDN(zone)] = id
+	})
+}
+
+// WithRequestID returns a context making calls with it send id as the
+// X-Request-Id header of their API requests, instead of a random ID per
+// request, e.g. to correlate them with the caller's own trace.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return withOptions(ctx, func(opts *callOptions) { opts.requestID = id })
+}
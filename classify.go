@@ -0,0 +1,39 @@
+package hetzner
+
+import (
+	"errors"
+	"net"
+	"net/http"
+)
+
+// IsRetryable reports whether err is likely transient, so the failed call
+// may succeed if repeated later: rate limiting, server errors, network
+// errors and an open circuit breaker.
+func IsRetryable(err error) bool {
+	if errors.Is(err, ErrCircuitOpen) {
+		return true
+	}
+	if hasStatus(err, http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// IsAuthError reports whether err was caused by a missing, invalid or
+// insufficient API token, i.e. wraps ErrUnauthorized or ErrForbidden.
+func IsAuthError(err error) bool {
+	return errors.Is(err, ErrUnauthorized) || errors.Is(err, ErrForbidden)
+}
+
+// IsNotFound reports whether err was caused by a missing zone or record.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrRecordNotFound) || errors.Is(err, ErrZoneNotFound) ||
+		errors.Is(err, ErrReverseZoneNotSupported) || hasStatus(err, http.StatusNotFound)
+}
+
+// IsRateLimited reports whether err was caused by exceeding the API's rate
+// limit. The APIError then tells the limit in RateLimit, if known.
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
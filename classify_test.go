@@ -0,0 +1,49 @@
+package hetzner_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/libdns/hetzner"
+)
+
+func Test_ErrorClassification(t *testing.T) {
+	testCases := []struct {
+		status      int
+		retryable   bool
+		auth        bool
+		notFound    bool
+		rateLimited bool
+	}{
+		{status: http.StatusTooManyRequests, retryable: true, rateLimited: true},
+		{status: http.StatusBadGateway, retryable: true},
+		{status: http.StatusUnauthorized, auth: true},
+		{status: http.StatusForbidden, auth: true},
+		{status: http.StatusNotFound, notFound: true},
+		{status: http.StatusBadRequest},
+	}
+
+	for _, c := range testCases {
+		p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/zones" {
+				fmt.Fprint(w, `{"zones":[{"id":"z","name":"example.com"}]}`)
+				return
+			}
+			w.WriteHeader(c.status)
+		})
+		_, err := p.GetRecords(context.TODO(), "example.com")
+
+		if got := [4]bool{hetzner.IsRetryable(err), hetzner.IsAuthError(err), hetzner.IsNotFound(err), hetzner.IsRateLimited(err)}; got != [4]bool{c.retryable, c.auth, c.notFound, c.rateLimited} {
+			t.Fatalf("%d: classified as %v => %v", c.status, got, err)
+		}
+	}
+
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"zones":[]}`)
+	})
+	if _, err := p.GetRecords(context.TODO(), "example.com"); !hetzner.IsNotFound(err) || hetzner.IsRetryable(err) {
+		t.Fatalf("missing zone misclassified => %v", err)
+	}
+}
@@ -3,219 +3,683 @@ package hetzner
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"net/http"
-	"net/url"
-	"strings"
 	"time"
 
 	"github.com/libdns/libdns"
 )
 
 type getAllRecordsResponse struct {
-	Records []record `json:"records"`
+	Records []APIRecord `json:"records"`
+	Meta    meta        `json:"meta"`
+}
+
+type getRecordResponse struct {
+	Record APIRecord `json:"record"`
 }
 
 type getAllZonesResponse struct {
-	Zones []zone `json:"zones"`
+	Zones []APIZone `json:"zones"`
+	Meta  meta      `json:"meta"`
+}
+
+type meta struct {
+	Pagination APIPagination `json:"pagination"`
 }
 
 type createRecordResponse struct {
-	Record record `json:"record"`
+	Record APIRecord `json:"record"`
 }
 
 type updateRecordResponse struct {
-	Record record `json:"record"`
+	Record APIRecord `json:"record"`
 }
 
-type zone struct {
-	ID string `json:"id"`
+type updateZoneRequest struct {
+	Name string `json:"name"`
+	TTL  int    `json:"ttl"`
 }
 
-type record struct {
-	ID     string `json:"id,omitempty"`
-	ZoneID string `json:"zone_id,omitempty"`
-	Type   string `json:"type"`
-	Name   string `json:"name"`
-	Value  string `json:"value"`
-	TTL    int    `json:"ttl"`
+// defaultBaseURL is the base URL of the Hetzner DNS API.
+const defaultBaseURL = "https://dns.hetzner.com/api/v1"
+
+// operation classifies API requests for applying per-operation timeouts.
+type operation int
+
+const (
+	opZoneLookup operation = iota
+	opRead
+	opWrite
+)
+
+// doRequest sends a request to the API path and returns the response body.
+// A non-nil payload is sent as JSON request body. Failed requests are retried
+// according to the provider's retry policy, with the same request ID.
+func (p *Provider) doRequest(ctx context.Context, op operation, method string, path string, payload interface{}) ([]byte, error) {
+	if err := p.validateOnce(); err != nil {
+		return nil, err
+	}
+
+	id := optionsFrom(ctx).requestID
+	if id == "" {
+		id = newRequestID()
+		ctx = withOptions(ctx, func(opts *callOptions) { opts.requestID = id })
+	}
+
+	var body *requestBody
+	if payload != nil {
+		var err error
+		body, err = newRequestBody(payload)
+		if err != nil {
+			return nil, err
+		}
+		defer body.release()
+	}
+
+	policy := p.retryPolicy()
+	if optionsFrom(ctx).noRetry {
+		policy.MaxAttempts = 1
+	}
+	for attempt := 1; ; attempt++ {
+		data, err := p.attempt(ctx, op, method, path, body)
+		if err == nil || attempt >= policy.MaxAttempts || ctx.Err() != nil || !policy.retryable(method, err) {
+			return data, err
+		}
+
+		delay := policy.delay(attempt, err)
+		p.logf("%s %s (request ID %s) failed (attempt %d of %d), retrying in %s: %v", method, path, id, attempt, policy.MaxAttempts, delay, err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
 }
 
-func doRequest(token string, request *http.Request) ([]byte, error) {
-	request.Header.Add("Auth-API-Token", token)
+// attempt performs a single request.
+func (p *Provider) attempt(ctx context.Context, op operation, method string, path string, body *requestBody) ([]byte, error) {
+	callerCtx := ctx
+	if timeout := p.requestTimeout(ctx, op); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
 
-	client := &http.Client{}
-	response, err := client.Do(request)
+	baseURL := p.baseURL
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	request, err := http.NewRequestWithContext(ctx, method, baseURL+path, nil)
 	if err != nil {
 		return nil, err
 	}
+	request.Header.Add("Auth-API-Token", p.AuthAPIToken)
+	request.Header.Set("User-Agent", p.userAgent())
+	request.Header.Set("X-Request-Id", optionsFrom(ctx).requestID)
+	if body != nil {
+		request.Body = body.reader()
+		request.GetBody = func() (io.ReadCloser, error) { return body.reader(), nil }
+		request.ContentLength = body.len()
+		request.Header.Set("Content-Type", "application/json")
+	}
 
-	if response.StatusCode < 200 || response.StatusCode >= 300 {
-		return nil, fmt.Errorf("%s (%d)", http.StatusText(response.StatusCode), response.StatusCode)
+	p.conditional.prepare(request, path)
+
+	if err := p.allowRequest(); err != nil {
+		if request.Body != nil {
+			request.Body.Close()
+		}
+		return nil, err
+	}
+	data, header, err := p.send(request)
+	p.recordResult(callerCtx, err)
+
+	if method == http.MethodGet {
+		if hasStatus(err, http.StatusNotModified) {
+			if cached, ok := p.conditional.cached(path); ok {
+				data, err = cached, nil
+			}
+		} else if err == nil {
+			p.conditional.store(path, header, data)
+		}
 	}
 
-	defer response.Body.Close()
-	data, err := ioutil.ReadAll(response.Body)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		apiErr.Method = method
+		apiErr.Path = path
+		apiErr.SentRequestID = optionsFrom(ctx).requestID
+		switch apiErr.StatusCode {
+		case http.StatusUnauthorized:
+			apiErr.Hint = p.unauthorizedHint()
+		case http.StatusForbidden:
+			apiErr.Hint = forbiddenHint
+		}
+	}
+
+	return data, err
+}
+
+func (p *Provider) send(request *http.Request) ([]byte, http.Header, error) {
+	response, err := p.httpClient().Do(request)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	defer response.Body.Close()
+
+	rateLimit := p.observeRateLimit(response.Header)
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		apiErr := &APIError{
+			StatusCode: response.StatusCode,
+			RequestID:  requestID(response.Header),
+			RateLimit:  rateLimit,
+			Message:    errorMessage(response.Body),
+			retryAfter: parseRetryAfter(response.Header.Get("Retry-After")),
+		}
+		if response.StatusCode == http.StatusUnprocessableEntity {
+			return nil, response.Header, newValidationError(apiErr)
+		}
+		return nil, response.Header, apiErr
+	}
+
+	var data bytes.Buffer
+	if response.ContentLength > 0 {
+		data.Grow(int(response.ContentLength))
+	}
+	if _, err := data.ReadFrom(response.Body); err != nil {
+		return nil, nil, err
 	}
 
-	return data, nil
+	return data.Bytes(), response.Header, nil
 }
 
-func getZoneID(ctx context.Context, token string, zone string) (string, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("https://dns.hetzner.com/api/v1/zones?name=%s", url.QueryEscape(zone)), nil)
-	data, err := doRequest(token, req)
+// errorMessage returns the message of an error response body like
+// {"error":{"message":"...","code":422}} or {"message":"..."}, or an empty
+// string if there is none.
+func errorMessage(body io.Reader) string {
+	var result struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+		Message string `json:"message"`
+	}
+	data, err := io.ReadAll(io.LimitReader(body, 64<<10))
+	if err != nil || json.Unmarshal(data, &result) != nil {
+		return ""
+	}
+	if result.Error.Message != "" {
+		return result.Error.Message
+	}
+	return result.Message
+}
+
+// requestIDHeaders are the response headers that may carry a request or
+// correlation ID.
+var requestIDHeaders = []string{"X-Request-Id", "X-Correlation-Id", "Request-Id"}
+
+// newRequestID returns a random ID for the X-Request-Id header.
+func newRequestID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+func requestID(header http.Header) string {
+	for _, name := range requestIDHeaders {
+		if id := header.Get(name); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// defaultRequestTimeout is used when Provider.DefaultRequestTimeout is not
+// set.
+const defaultRequestTimeout = 30 * time.Second
+
+// requestTimeout returns the timeout for a single request of the operation:
+// the one set with WithTimeout, the configured per-operation timeout or, if
+// there is none and ctx has no deadline either, the default request timeout.
+func (p *Provider) requestTimeout(ctx context.Context, op operation) time.Duration {
+	if timeout := optionsFrom(ctx).timeout; timeout > 0 {
+		return timeout
+	}
+	if timeout := p.timeout(op); timeout > 0 {
+		return timeout
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return 0
+	}
+	if p.DefaultRequestTimeout == 0 {
+		return defaultRequestTimeout
+	}
+	return p.DefaultRequestTimeout
+}
+
+// timeout returns the configured timeout for requests of the operation.
+func (p *Provider) timeout(op operation) time.Duration {
+	switch op {
+	case opZoneLookup:
+		return p.ZoneLookupTimeout
+	case opRead:
+		return p.ReadTimeout
+	default:
+		return p.WriteTimeout
+	}
+}
+
+func (p *Provider) getZoneID(ctx context.Context, zone string) (string, error) {
+	if id, ok := optionsFrom(ctx).zoneIDs[zone]; ok {
+		return id, nil
+	}
+	if !optionsFrom(ctx).skipCache {
+		if id, ok := p.zoneIDs.get(ctx, zone); ok {
+			return id, nil
+		}
+		if _, ok := p.missingZones.get(ctx, zone); ok {
+			return "", zoneNotFound(zone)
+		}
+	}
+
+	z, err := p.getZone(ctx, zone)
+	if errors.Is(err, ErrZoneNotFound) || errors.Is(err, ErrReverseZoneNotSupported) {
+		p.missingZones.set(ctx, zone, "")
+	}
 	if err != nil {
 		return "", err
 	}
 
-	result := getAllZonesResponse{}
-	if err := json.Unmarshal(data, &result); err != nil {
-		return "", err
+	p.missingZones.delete(ctx, zone)
+	p.zoneIDs.set(ctx, zone, z.ID)
+	return z.ID, nil
+}
+
+// getZone looks up the zone by name.
+func (p *Provider) getZone(ctx context.Context, zone string) (Zone, error) {
+	zones, _, err := p.api().ListZones(ctx, zone, 0, 0)
+	if hasStatus(err, http.StatusNotFound) {
+		return Zone{}, zoneNotFound(zone)
 	}
+	if err != nil {
+		return Zone{}, err
+	}
+
+	if len(zones) == 0 {
+		return Zone{}, zoneNotFound(zone)
+	}
+	if len(zones) > 1 {
+		return Zone{}, errors.New("zone is ambiguous")
+	}
+
+	return toZone(zones[0]), nil
+}
+
+// updateZoneTTL sets the default TTL of the zone.
+func (p *Provider) updateZoneTTL(ctx context.Context, z Zone, ttl time.Duration) error {
+	return p.api().UpdateZone(ctx, APIZone{ID: z.ID, Name: z.Name, TTL: int(ttl.Seconds())})
+}
+
+func toZone(z APIZone) Zone {
+	return Zone{
+		ID:           z.ID,
+		Name:         z.Name,
+		TTL:          time.Duration(z.TTL) * time.Second,
+		RecordsCount: z.RecordsCount,
+	}
+}
+
+func (p *Provider) getAllZones(ctx context.Context) ([]Zone, error) {
+	zones := []Zone{}
+	for page := 1; ; page++ {
+		result, pagination, err := p.api().ListZones(ctx, "", page, 100)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, z := range result {
+			zones = append(zones, toZone(z))
+		}
+
+		if page >= pagination.LastPage {
+			return zones, nil
+		}
+	}
+}
 
-	if len(result.Zones) > 1 {
-		return "", errors.New("zone is ambiguous")
+func (p *Provider) getRecord(ctx context.Context, id string) (libdns.Record, error) {
+	result, err := p.api().GetRecord(ctx, id)
+	if err != nil {
+		return libdns.Record{}, err
 	}
 
-	return result.Zones[0].ID, nil
+	record := p.incomingRecord("", result)
+	if !p.inScope("", record) && !optionsFrom(ctx).unrestricted {
+		return libdns.Record{}, fmt.Errorf("%w: %s", ErrRecordNotFound, id)
+	}
+	return record, nil
 }
 
-func getAllRecords(ctx context.Context, token string, zone string) ([]libdns.Record, error) {
-	zoneID, err := getZoneID(ctx, token, zone)
+// getRecordInZone is like getRecord, but the record must belong to zone.
+func (p *Provider) getRecordInZone(ctx context.Context, zone string, id string) (libdns.Record, error) {
+	zoneID, err := p.getZoneID(ctx, zone)
 	if err != nil {
-		return nil, err
+		return libdns.Record{}, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("https://dns.hetzner.com/api/v1/records?zone_id=%s", zoneID), nil)
-	data, err := doRequest(token, req)
+	result, err := p.api().GetRecord(ctx, id)
+	if err != nil {
+		return libdns.Record{}, err
+	}
+	if result.ZoneID != "" && result.ZoneID != zoneID {
+		return libdns.Record{}, fmt.Errorf("%w: %s in zone %s", ErrRecordNotFound, id, zone)
+	}
+
+	record := p.incomingRecord(zone, result)
+	if !p.inScope(zone, record) && !optionsFrom(ctx).unrestricted {
+		return libdns.Record{}, fmt.Errorf("%w: %s", ErrRecordNotFound, id)
+	}
+	return record, nil
+}
+
+func (p *Provider) getAllRecords(ctx context.Context, zone string) ([]libdns.Record, error) {
+	zoneID, err := p.getZoneID(ctx, zone)
 	if err != nil {
 		return nil, err
 	}
 
-	result := getAllRecordsResponse{}
-	if err := json.Unmarshal(data, &result); err != nil {
+	result, _, err := p.api().ListRecords(ctx, zoneID, 0, 0)
+	if err != nil {
 		return nil, err
 	}
 
-	records := []libdns.Record{}
-	for _, r := range result.Records {
-		records = append(records, libdns.Record{
-			ID:    r.ID,
-			Type:  r.Type,
-			Name:  r.Name,
-			Value: r.Value,
-			TTL:   time.Duration(r.TTL) * time.Second,
-		})
+	records := make([]libdns.Record, 0, len(result))
+	for _, r := range result {
+		records = append(records, p.incomingRecord(zone, r))
 	}
 
-	return records, nil
+	if optionsFrom(ctx).unrestricted {
+		return records, nil
+	}
+	return p.scoped(zone, records), nil
 }
 
-func createRecord(ctx context.Context, token string, zone string, r libdns.Record) (libdns.Record, error) {
-	zoneID, err := getZoneID(ctx, token, zone)
+// getRecordsPage fetches one page of the zone's records. It also returns the
+// number of the last page.
+func (p *Provider) getRecordsPage(ctx context.Context, zone string, zoneID string, page int, perPage int) ([]libdns.Record, int, error) {
+	result, pagination, err := p.api().ListRecords(ctx, zoneID, page, perPage)
 	if err != nil {
-		return libdns.Record{}, err
+		return nil, 0, err
 	}
 
-	reqData := record{
-		ZoneID: zoneID,
-		Type:   r.Type,
-		Name:   normalizeRecordName(r.Name, zone),
-		Value:  r.Value,
-		TTL:    int(r.TTL.Seconds()),
+	records := make([]libdns.Record, 0, len(result))
+	for _, r := range result {
+		records = append(records, p.incomingRecord(zone, r))
 	}
 
-	reqBuffer, err := json.Marshal(reqData)
+	return records, pagination.LastPage, nil
+}
+
+func (p *Provider) createRecord(ctx context.Context, zone string, r libdns.Record) (libdns.Record, error) {
+	reg, err := p.registry(ctx, zone)
 	if err != nil {
 		return libdns.Record{}, err
 	}
+	if err := reg.check(ctx, r); err != nil {
+		return libdns.Record{}, err
+	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", "https://dns.hetzner.com/api/v1/records", bytes.NewBuffer(reqBuffer))
-	data, err := doRequest(token, req)
+	r, err = p.outgoingRecord(ctx, zone, r)
 	if err != nil {
 		return libdns.Record{}, err
 	}
 
-	result := createRecordResponse{}
-	if err := json.Unmarshal(data, &result); err != nil {
+	zoneID, err := p.getZoneID(ctx, zone)
+	if err != nil {
 		return libdns.Record{}, err
 	}
 
-	return libdns.Record{
-		ID:    result.Record.ID,
-		Type:  result.Record.Type,
-		Name:  result.Record.Name,
-		Value: result.Record.Value,
-		TTL:   time.Duration(result.Record.TTL) * time.Second,
-	}, nil
+	ttl, err := p.apiTTL(r)
+	if err != nil {
+		return libdns.Record{}, err
+	}
+
+	result, err := p.api().CreateRecord(ctx, APIRecord{
+		ZoneID: zoneID,
+		Type:   r.Type,
+		Name:   r.Name,
+		Value:  r.Value,
+		TTL:    ttl,
+	})
+	if err != nil {
+		return libdns.Record{}, withRecord(err, r)
+	}
+
+	created := p.incomingRecord(zone, result)
+	return created, p.claim(ctx, reg, created, true)
 }
 
-func deleteRecord(ctx context.Context, token string, record libdns.Record) error {
-	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("https://dns.hetzner.com/api/v1/records/%s", record.ID), nil)
-	_, err = doRequest(token, req)
+func (p *Provider) deleteRecord(ctx context.Context, zone string, record libdns.Record) error {
+	if err := p.checkStored(ctx, zone, record.ID); err != nil {
+		return err
+	}
+
+	reg, err := p.registry(ctx, zone)
 	if err != nil {
 		return err
 	}
+	if reg != nil && len(record.Type) == 0 {
+		if record, err = p.getRecord(ctx, record.ID); err != nil {
+			return err
+		}
+	}
+	if err := reg.check(ctx, record); err != nil {
+		return err
+	}
 
-	return nil
+	if err := p.api().DeleteRecord(ctx, record.ID); err != nil {
+		return err
+	}
+
+	return p.release(ctx, reg, record)
 }
 
-func updateRecord(ctx context.Context, token string, zone string, r libdns.Record) (libdns.Record, error) {
-	zoneID, err := getZoneID(ctx, token, zone)
+func (p *Provider) updateRecord(ctx context.Context, zone string, r libdns.Record) (libdns.Record, error) {
+	zoneID, err := p.getZoneID(ctx, zone)
+	if err != nil {
+		return libdns.Record{}, err
+	}
+
+	if err := p.checkStored(ctx, zone, r.ID); err != nil {
+		return libdns.Record{}, err
+	}
+
+	r, err = p.mergeWithExistingRecord(ctx, zone, r)
+	if err != nil {
+		return libdns.Record{}, err
+	}
+
+	reg, err := p.registry(ctx, zone)
+	if err != nil {
+		return libdns.Record{}, err
+	}
+	if err := reg.check(ctx, r); err != nil {
+		return libdns.Record{}, err
+	}
+
+	r, err = p.outgoingRecord(ctx, zone, r)
+	if err != nil {
+		return libdns.Record{}, err
+	}
+
+	ttl, err := p.apiTTL(r)
 	if err != nil {
 		return libdns.Record{}, err
 	}
 
-	reqData := record{
+	result, err := p.api().UpdateRecord(ctx, APIRecord{
+		ID:     r.ID,
 		ZoneID: zoneID,
 		Type:   r.Type,
-		Name:   normalizeRecordName(r.Name, zone),
+		Name:   r.Name,
 		Value:  r.Value,
-		TTL:    int(r.TTL.Seconds()),
+		TTL:    ttl,
+	})
+	if err != nil {
+		return libdns.Record{}, withRecord(err, r)
 	}
 
-	reqBuffer, err := json.Marshal(reqData)
-	if err != nil {
-		return libdns.Record{}, err
+	updated := p.incomingRecord(zone, result)
+	return updated, p.claim(ctx, reg, updated, false)
+}
+
+// mergeWithExistingRecord fills the fields the caller left unset in r with
+// the values currently stored for the record, so a PUT with only a new value
+// doesn't reset the TTL (or anything else) of the record.
+func (p *Provider) mergeWithExistingRecord(ctx context.Context, zone string, r libdns.Record) (libdns.Record, error) {
+	if len(r.Type) > 0 && len(r.Name) > 0 && len(r.Value) > 0 && r.TTL != 0 {
+		return r, nil
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("https://dns.hetzner.com/api/v1/records/%s", r.ID), bytes.NewBuffer(reqBuffer))
-	data, err := doRequest(token, req)
+	existing, err := p.getRecord(ctx, r.ID)
 	if err != nil {
 		return libdns.Record{}, err
 	}
 
-	result := updateRecordResponse{}
-	if err := json.Unmarshal(data, &result); err != nil {
+	return fillUnset(r, existing), nil
+}
+
+// fillUnset returns r with its unset fields taken from existing.
+func fillUnset(r libdns.Record, existing libdns.Record) libdns.Record {
+	if len(r.Type) == 0 {
+		r.Type = existing.Type
+	}
+	if len(r.Name) == 0 {
+		r.Name = existing.Name
+	}
+	if len(r.Value) == 0 {
+		r.Value = existing.Value
+	}
+	if r.TTL == 0 {
+		r.TTL = existing.TTL
+	}
+	return r
+}
+
+// createOrUpdateRecord updates the existing record r refers to by ID or, for
+// records without ID, by name and type, and creates r if there is none.
+// Existing records are looked up in index if it isn't nil, and fetched from
+// the API otherwise. Replacing an apex NS or SOA record is refused, see
+// checkDangerous; creating one is not.
+func (p *Provider) createOrUpdateRecord(ctx context.Context, zone string, r libdns.Record, index *recordIndex) (libdns.Record, error) {
+	if len(r.ID) > 0 {
+		if err := p.checkDangerous(ctx, zone, r); err != nil {
+			return libdns.Record{}, err
+		}
+	} else {
+		var existing *libdns.Record
+		if index != nil {
+			existing = index.claim(r)
+		} else {
+			var err error
+			existing, err = p.findRecord(ctx, zone, r)
+			if err != nil {
+				return libdns.Record{}, err
+			}
+		}
+		if existing == nil {
+			return p.createRecordWithRecovery(ctx, zone, r)
+		}
+		if !sameValue(*existing, r) {
+			if err := p.checkDangerousStored(zone, *existing); err != nil {
+				return libdns.Record{}, err
+			}
+		}
+		r = fillUnset(r, *existing)
+		r.ID = existing.ID
+	}
+
+	return p.updateRecordWithRecovery(ctx, zone, r)
+}
+
+// createRecordWithRecovery creates r. If the API reports a conflict, the
+// zone's records are refetched: an identical record already existing is
+// returned as is, otherwise the create is retried once. Other errors, like
+// a 422 for an invalid record, are returned as they are.
+func (p *Provider) createRecordWithRecovery(ctx context.Context, zone string, r libdns.Record) (libdns.Record, error) {
+	created, err := p.createRecord(ctx, zone, r)
+	if !hasStatus(err, http.StatusConflict) {
+		return created, err
+	}
+
+	existing, lookupErr := p.findRecord(ctx, zone, r)
+	if lookupErr != nil {
 		return libdns.Record{}, err
 	}
+	if existing != nil && sameValue(*existing, r) {
+		return *existing, nil
+	}
 
-	return libdns.Record{
-		ID:    result.Record.ID,
-		Type:  result.Record.Type,
-		Name:  result.Record.Name,
-		Value: result.Record.Value,
-		TTL:   time.Duration(result.Record.TTL) * time.Second,
-	}, nil
+	return p.createRecord(ctx, zone, r)
 }
 
-func createOrUpdateRecord(ctx context.Context, token string, zone string, r libdns.Record) (libdns.Record, error) {
-	if len(r.ID) == 0 {
-		return createRecord(ctx, token, zone, r)
+// updateRecordWithRecovery updates r. If the record's ID went stale, the
+// zone's records are refetched and the update is retried once against the
+// record with the same name and type. If there is none anymore, it returns
+// an error wrapping ErrRecordNotFound rather than recreating the record.
+func (p *Provider) updateRecordWithRecovery(ctx context.Context, zone string, r libdns.Record) (libdns.Record, error) {
+	updated, err := p.updateRecord(ctx, zone, r)
+	if !hasStatus(err, http.StatusNotFound, http.StatusConflict) {
+		return updated, err
 	}
 
-	return updateRecord(ctx, token, zone, r)
+	existing, lookupErr := p.findRecord(ctx, zone, r)
+	if lookupErr != nil {
+		return libdns.Record{}, err
+	}
+	if existing == nil {
+		return libdns.Record{}, fmt.Errorf("%w: %s", ErrRecordNotFound, r.ID)
+	}
+	if !sameValue(*existing, r) {
+		if err := p.checkDangerousStored(zone, *existing); err != nil {
+			return libdns.Record{}, err
+		}
+	}
+
+	r.ID = existing.ID
+	return p.updateRecord(ctx, zone, r)
 }
 
-func normalizeRecordName(recordName string, zone string) string {
-	// Workaround for https://github.com/caddy-dns/hetzner/issues/3
-	// Can be removed after https://github.com/libdns/libdns/issues/12
-	normalized := unFQDN(recordName)
-	normalized = strings.TrimSuffix(normalized, unFQDN(zone))
-	return unFQDN(normalized)
+// findRecord looks up an existing record with the same name and type as r.
+// A record that also has the same value is preferred. It returns nil if the
+// zone has no record of that name and type.
+func (p *Provider) findRecord(ctx context.Context, zone string, r libdns.Record) (*libdns.Record, error) {
+	records, err := p.getAllRecords(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	var found *libdns.Record
+	for i, record := range records {
+		if !sameName(record.Name, r.Name, zone) || record.Type != r.Type {
+			continue
+		}
+		if sameValue(record, r) {
+			return &records[i], nil
+		}
+		if found == nil {
+			found = &records[i]
+		}
+	}
+
+	return found, nil
 }
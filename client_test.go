@@ -0,0 +1,812 @@
+package hetzner_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/libdns/hetzner"
+	"github.com/libdns/libdns"
+)
+
+func newTestProvider(t testing.TB, handler http.HandlerFunc, opts ...hetzner.Option) *hetzner.Provider {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return hetzner.New("token", append([]hetzner.Option{hetzner.WithBaseURL(server.URL)}, opts...)...)
+}
+
+const testRecordResponse = `{"record":{"id":"1","zone_id":"z","type":"TXT","name":"test","value":"test","ttl":120}}`
+
+func Test_Retry(t *testing.T) {
+	var calls int32
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, testRecordResponse)
+	})
+	p.Retry = &hetzner.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+	if _, err := p.GetRecord(context.TODO(), "1"); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 {
+		t.Fatalf("calls != 3 => %d", calls)
+	}
+}
+
+func Test_RetryNotRetryable(t *testing.T) {
+	var calls int32
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusNotFound)
+	})
+	p.Retry = &hetzner.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+	_, err := p.GetRecord(context.TODO(), "1")
+	var apiErr *hetzner.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 APIError, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("calls != 1 => %d", calls)
+	}
+}
+
+func Test_RequestID(t *testing.T) {
+	var mu sync.Mutex
+	var ids []string
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		ids = append(ids, r.Header.Get("X-Request-Id"))
+		mu.Unlock()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	p.Retry = &hetzner.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}
+
+	_, err := p.GetRecord(context.TODO(), "1")
+	var apiErr *hetzner.APIError
+	if !errors.As(err, &apiErr) || apiErr.SentRequestID == "" || len(ids) != 2 || ids[0] != apiErr.SentRequestID || ids[1] != ids[0] {
+		t.Fatalf("unexpected request IDs %v => %v", ids, err)
+	}
+
+	_, err = p.GetRecord(hetzner.WithRequestID(context.TODO(), "trace-1"), "1")
+	if !strings.Contains(err.Error(), "sent request ID trace-1") || ids[2] != "trace-1" {
+		t.Fatalf("unexpected request IDs %v => %v", ids, err)
+	}
+}
+
+func Test_CircuitBreaker(t *testing.T) {
+	var calls int32
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, testRecordResponse)
+	})
+	p.BreakerThreshold = 2
+	p.BreakerCooldown = 50 * time.Millisecond
+
+	for i := 0; i < 2; i++ {
+		if _, err := p.GetRecord(context.TODO(), "1"); err == nil {
+			t.Fatal("expected error")
+		}
+	}
+	if p.BreakerState() != hetzner.BreakerOpen {
+		t.Fatalf("p.BreakerState() != open => %s", p.BreakerState())
+	}
+	if _, err := p.GetRecord(context.TODO(), "1"); !errors.Is(err, hetzner.ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if _, err := p.GetRecord(context.TODO(), "1"); err != nil {
+		t.Fatal(err)
+	}
+	if p.BreakerState() != hetzner.BreakerClosed {
+		t.Fatalf("p.BreakerState() != closed => %s", p.BreakerState())
+	}
+}
+
+func Test_CircuitBreakerCanceled(t *testing.T) {
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	p.BreakerThreshold = 2
+	p.BreakerCooldown = 50 * time.Millisecond
+
+	canceled, cancel := context.WithCancel(context.TODO())
+	cancel()
+
+	// a canceled request neither resets nor adds to the failures
+	p.GetRecord(context.TODO(), "1")
+	p.GetRecord(canceled, "1")
+	if p.BreakerState() != hetzner.BreakerClosed {
+		t.Fatalf("p.BreakerState() != closed => %s", p.BreakerState())
+	}
+	p.GetRecord(context.TODO(), "1")
+	if p.BreakerState() != hetzner.BreakerOpen {
+		t.Fatalf("p.BreakerState() != open => %s", p.BreakerState())
+	}
+
+	// nor does it close a half-open breaker
+	time.Sleep(60 * time.Millisecond)
+	if _, err := p.GetRecord(canceled, "1"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err != context.Canceled => %v", err)
+	}
+	if p.BreakerState() != hetzner.BreakerHalfOpen {
+		t.Fatalf("p.BreakerState() != half-open => %s", p.BreakerState())
+	}
+}
+
+func Test_RecordsSeq(t *testing.T) {
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/zones" {
+			fmt.Fprint(w, `{"zones":[{"id":"z","name":"example.com"}]}`)
+			return
+		}
+		page := r.URL.Query().Get("page")
+		fmt.Fprintf(w, `{"records":[{"id":"%s","type":"TXT","name":"r%s","value":"v"}],"meta":{"pagination":{"page":%s,"last_page":3}}}`, page, page, page)
+	})
+
+	var ids []string
+	for record, err := range p.RecordsSeq(context.TODO(), "example.com.") {
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, record.ID)
+		if len(ids) == 2 {
+			break
+		}
+	}
+	if fmt.Sprint(ids) != "[1 2]" {
+		t.Fatalf("ids != [1 2] => %v", ids)
+	}
+}
+
+func Test_ZoneCache(t *testing.T) {
+	var zoneLookups int32
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/zones" {
+			atomic.AddInt32(&zoneLookups, 1)
+			fmt.Fprint(w, `{"zones":[{"id":"z","name":"example.com"}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"records":[]}`)
+	}, hetzner.WithCache(time.Minute))
+
+	for i := 0; i < 3; i++ {
+		if _, err := p.GetRecords(context.TODO(), "example.com"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if zoneLookups != 1 {
+		t.Fatalf("zoneLookups != 1 => %d", zoneLookups)
+	}
+
+	if _, err := p.GetRecords(hetzner.WithSkipCache(context.TODO()), "example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if zoneLookups != 2 {
+		t.Fatalf("zoneLookups != 2 => %d", zoneLookups)
+	}
+}
+
+func Test_Validate(t *testing.T) {
+	p := hetzner.New(" token\n", hetzner.WithBaseURL("dns.example.com"), hetzner.WithRetry(hetzner.RetryPolicy{Jitter: 2}))
+
+	err := p.Validate()
+	if !errors.Is(err, hetzner.ErrInvalidConfig) {
+		t.Fatalf("expected ErrInvalidConfig, got %v", err)
+	}
+	for _, s := range []string{"whitespace", "base URL", "Jitter"} {
+		if !strings.Contains(err.Error(), s) {
+			t.Fatalf("error doesn't mention %q: %v", s, err)
+		}
+	}
+
+	if _, err := p.GetRecords(context.TODO(), "example.com"); !errors.Is(err, hetzner.ErrInvalidConfig) {
+		t.Fatalf("expected ErrInvalidConfig, got %v", err)
+	}
+}
+
+func Test_ValidateAfterFix(t *testing.T) {
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, testRecordResponse)
+	})
+	p.AuthAPIToken = ""
+
+	if _, err := p.GetRecord(context.TODO(), "1"); !errors.Is(err, hetzner.ErrInvalidConfig) {
+		t.Fatalf("expected ErrInvalidConfig, got %v", err)
+	}
+
+	// the failure isn't remembered once the configuration is fixed
+	p.AuthAPIToken = "token"
+	if _, err := p.GetRecord(context.TODO(), "1"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_UserAgent(t *testing.T) {
+	var userAgent string
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		fmt.Fprint(w, testRecordResponse)
+	})
+	p.UserAgent = "caddy/2"
+
+	if _, err := p.GetRecord(context.TODO(), "1"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(userAgent, "libdns-hetzner/") || !strings.HasSuffix(userAgent, " caddy/2") {
+		t.Fatalf("unexpected User-Agent %q", userAgent)
+	}
+}
+
+func Test_ZoneNotFound(t *testing.T) {
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"zones":[]}`)
+	})
+
+	_, err := p.GetRecords(context.TODO(), "example.com.")
+	if !errors.Is(err, hetzner.ErrZoneNotFound) {
+		t.Fatalf("err != ErrZoneNotFound => %v", err)
+	}
+
+	_, err = p.GetRecords(context.TODO(), "2.0.192.in-addr.arpa.")
+	if !errors.Is(err, hetzner.ErrReverseZoneNotSupported) {
+		t.Fatalf("err != ErrReverseZoneNotSupported => %v", err)
+	}
+}
+
+func Test_WaitForSerial(t *testing.T) {
+	var serial int32 = 2024010101
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/zones" {
+			fmt.Fprint(w, `{"zones":[{"id":"z","name":"example.com"}]}`)
+			return
+		}
+		fmt.Fprintf(w, `{"records":[{"id":"1","zone_id":"z","type":"SOA","name":"@","value":"hydrogen.ns.hetzner.com. dns.hetzner.com. %d 86400 10800 3600000 3600"}]}`, atomic.AddInt32(&serial, 1)-1)
+	})
+
+	before, err := p.ZoneSerial(context.TODO(), "example.com.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before != 2024010101 {
+		t.Fatalf("serial != 2024010101 => %d", before)
+	}
+
+	after, err := p.WaitForSerial(context.TODO(), "example.com.", before, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after != before+1 {
+		t.Fatalf("serial != %d => %d", before+1, after)
+	}
+}
+
+func Test_OwningZone(t *testing.T) {
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"zones":[{"id":"1","name":"example.com"},{"id":"2","name":"sub.example.com"}],"meta":{"pagination":{"page":1,"last_page":1}}}`)
+	})
+
+	testCases := []struct {
+		fqdn string
+		zone string
+		name string
+	}{
+		{fqdn: "www.example.com.", zone: "example.com", name: "www"},
+		{fqdn: "a.b.sub.example.com.", zone: "sub.example.com", name: "a.b"},
+		{fqdn: "sub.example.com.", zone: "sub.example.com", name: "@"},
+		{fqdn: "www.notsub.example.com", zone: "example.com", name: "www.notsub"},
+	}
+	for _, c := range testCases {
+		zone, name, err := p.OwningZone(context.TODO(), c.fqdn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if zone != c.zone || name != c.name {
+			t.Fatalf("%s: %s, %s != %s, %s", c.fqdn, zone, name, c.zone, c.name)
+		}
+	}
+
+	if _, _, err := p.OwningZone(context.TODO(), "www.example.org."); !errors.Is(err, hetzner.ErrZoneNotFound) {
+		t.Fatalf("err != ErrZoneNotFound => %v", err)
+	}
+}
+
+func Test_StrictNames(t *testing.T) {
+	var mu sync.Mutex
+	var names []string
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/zones" {
+			fmt.Fprint(w, `{"zones":[{"id":"z","name":"example.com"}]}`)
+			return
+		}
+		var record struct {
+			Name string `json:"name"`
+		}
+		json.NewDecoder(r.Body).Decode(&record)
+		mu.Lock()
+		names = append(names, record.Name)
+		mu.Unlock()
+		fmt.Fprint(w, testRecordResponse)
+	})
+	p.StrictNames = true
+
+	records := []libdns.Record{
+		{Type: "TXT", Name: "www.example.com", Value: "test"},
+		{Type: "TXT", Name: "@", Value: "test"},
+	}
+	if _, err := p.AppendRecords(context.TODO(), "example.com.", records); err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(names)
+	if strings.Join(names, ",") != "@,www.example.com" {
+		t.Fatalf("unexpected names => %v", names)
+	}
+
+	for _, name := range []string{"www.example.com.", ""} {
+		_, err := p.AppendRecords(context.TODO(), "example.com.", []libdns.Record{{Type: "TXT", Name: name, Value: "test"}})
+		if !errors.Is(err, hetzner.ErrInvalidRecord) {
+			t.Fatalf("%q: err != ErrInvalidRecord => %v", name, err)
+		}
+	}
+}
+
+func Test_RecordHooks(t *testing.T) {
+	var sent string
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/zones" {
+			fmt.Fprint(w, `{"zones":[{"id":"z","name":"example.com"}]}`)
+			return
+		}
+		var record struct {
+			Name string `json:"name"`
+		}
+		json.NewDecoder(r.Body).Decode(&record)
+		sent = record.Name
+		fmt.Fprintf(w, `{"record":{"id":"1","zone_id":"z","type":"TXT","name":"%s","value":"test"}}`, record.Name)
+	})
+	p.OutgoingRecordHook = func(zone string, r libdns.Record) libdns.Record {
+		r.Name += ".staging"
+		return r
+	}
+	p.IncomingRecordHook = func(zone string, r libdns.Record) libdns.Record {
+		r.Name = strings.TrimSuffix(r.Name, ".staging")
+		return r
+	}
+
+	created, err := p.AppendRecords(context.TODO(), "example.com.", []libdns.Record{{Type: "TXT", Name: "www.example.com.", Value: "test"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent != "www.staging" {
+		t.Fatalf("sent name != www.staging => %s", sent)
+	}
+	if created[0].Name != "www" {
+		t.Fatalf("returned name != www => %s", created[0].Name)
+	}
+}
+
+func Test_ValueTransforms(t *testing.T) {
+	var sent string
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/zones" {
+			fmt.Fprint(w, `{"zones":[{"id":"z","name":"example.com"}]}`)
+			return
+		}
+		var record struct {
+			Value string `json:"value"`
+		}
+		json.NewDecoder(r.Body).Decode(&record)
+		sent = record.Value
+		fmt.Fprint(w, `{"record":{"id":"1","zone_id":"z","type":"CNAME","name":"www","value":"Target.Example.NET.corp"}}`)
+	})
+	p.ValueTransforms = []hetzner.ValueTransform{
+		hetzner.LowercaseValues("CNAME"),
+		hetzner.TrimValueSuffix("corp", "CNAME"),
+		hetzner.LowercaseValues("TXT"),
+	}
+
+	created, err := p.AppendRecords(context.TODO(), "example.com.", []libdns.Record{{Type: "CNAME", Name: "www", Value: "Target.Example.NET.corp"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent != "target.example.net." {
+		t.Fatalf("sent value != target.example.net. => %s", sent)
+	}
+	if created[0].Value != "target.example.net." {
+		t.Fatalf("returned value != target.example.net. => %s", created[0].Value)
+	}
+}
+
+func Test_CallOptions(t *testing.T) {
+	var calls int32
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.URL.Path == "/records/slow" {
+			time.Sleep(100 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	p.Retry = &hetzner.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+	if _, err := p.GetRecord(hetzner.WithNoRetry(context.TODO()), "1"); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 1 {
+		t.Fatalf("calls != 1 => %d", calls)
+	}
+
+	ctx := hetzner.WithNoRetry(hetzner.WithTimeout(context.TODO(), 10*time.Millisecond))
+	if _, err := p.GetRecord(ctx, "slow"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err != context.DeadlineExceeded => %v", err)
+	}
+}
+
+func Test_ConditionalRequests(t *testing.T) {
+	var notModified int32
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/zones" {
+			fmt.Fprint(w, `{"zones":[{"id":"z","name":"example.com"}]}`)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, `{"records":[{"id":"1","type":"TXT","name":"test","value":"test"}]}`)
+	}, hetzner.WithConditionalRequests())
+
+	for i := 0; i < 3; i++ {
+		records, err := p.GetRecords(context.TODO(), "example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != 1 || records[0].ID != "1" {
+			t.Fatalf("unexpected records => %+v", records)
+		}
+	}
+	if notModified != 2 {
+		t.Fatalf("notModified != 2 => %d", notModified)
+	}
+}
+
+func Test_NegativeCache(t *testing.T) {
+	var zoneLookups int32
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&zoneLookups, 1)
+		fmt.Fprint(w, `{"zones":[]}`)
+	}, hetzner.WithNegativeCache(time.Minute))
+
+	for i := 0; i < 3; i++ {
+		if _, err := p.GetRecords(context.TODO(), "example.com."); !errors.Is(err, hetzner.ErrZoneNotFound) {
+			t.Fatalf("err != ErrZoneNotFound => %v", err)
+		}
+	}
+	if zoneLookups != 1 {
+		t.Fatalf("zoneLookups != 1 => %d", zoneLookups)
+	}
+
+	p.ForgetMissingZone("example.com.")
+	p.GetRecords(context.TODO(), "example.com.")
+	if zoneLookups != 2 {
+		t.Fatalf("zoneLookups != 2 => %d", zoneLookups)
+	}
+}
+
+func Test_FlushCache(t *testing.T) {
+	var zoneLookups int32
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/zones" {
+			atomic.AddInt32(&zoneLookups, 1)
+			fmt.Fprint(w, `{"zones":[{"id":"z","name":"example.com"}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"records":[]}`)
+	}, hetzner.WithCache(time.Minute))
+
+	lookups := func() int32 {
+		if _, err := p.GetRecords(context.TODO(), "example.com"); err != nil {
+			t.Fatal(err)
+		}
+		return atomic.LoadInt32(&zoneLookups)
+	}
+
+	if n := lookups(); n != 1 {
+		t.Fatalf("zoneLookups != 1 => %d", n)
+	}
+	p.FlushCache("example.com.")
+	if n := lookups(); n != 2 {
+		t.Fatalf("zoneLookups != 2 => %d", n)
+	}
+	p.FlushAll()
+	if n := lookups(); n != 3 {
+		t.Fatalf("zoneLookups != 3 => %d", n)
+	}
+	if n := lookups(); n != 3 {
+		t.Fatalf("zoneLookups != 3 => %d", n)
+	}
+}
+
+func Test_SharedCache(t *testing.T) {
+	var zoneLookups int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/zones" {
+			atomic.AddInt32(&zoneLookups, 1)
+			fmt.Fprint(w, `{"zones":[{"id":"z","name":"example.com"}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"records":[]}`)
+	}
+
+	cache := hetzner.NewMemoryCache()
+	for i := 0; i < 2; i++ {
+		p := newTestProvider(t, handler, hetzner.WithCache(time.Minute))
+		p.Cache = cache
+		if _, err := p.GetRecords(context.TODO(), "example.com"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if zoneLookups != 1 {
+		t.Fatalf("zoneLookups != 1 => %d", zoneLookups)
+	}
+}
+
+func Test_MaxChangesPerApply(t *testing.T) {
+	p, api := newFakeProvider(t, "A www 192.0.2.1", "A mail 192.0.2.2", "TXT @ v=spf1 -all")
+	p.MaxChangesPerApply = 2
+
+	plan, err := p.SyncRecords(context.TODO(), "example.com", []libdns.Record{{Type: "A", Name: "www", Value: "192.0.2.1"}, {Type: "A", Name: "web", Value: "192.0.2.3"}})
+	var tooMany *hetzner.TooManyChangesError
+	if !errors.As(err, &tooMany) || !errors.Is(err, hetzner.ErrTooManyChanges) {
+		t.Fatalf("err is not a TooManyChangesError => %v", err)
+	}
+	if plan.Len() != 3 || tooMany.Plan.Len() != 3 || tooMany.Limit != 2 {
+		t.Fatalf("unexpected refused plan => %+v", tooMany)
+	}
+	if records := api.dump("z"); len(records) != 3 {
+		t.Fatalf("records were changed => %v", records)
+	}
+
+	_, err = p.SetRecords(context.TODO(), "example.com", []libdns.Record{
+		{Type: "A", Name: "www", Value: "192.0.2.9"},
+		{Type: "A", Name: "mail", Value: "192.0.2.9"},
+		{Type: "A", Name: "web", Value: "192.0.2.9"},
+	})
+	if !errors.As(err, &tooMany) || len(tooMany.Plan.Update) != 2 || len(tooMany.Plan.Create) != 1 {
+		t.Fatalf("unexpected SetRecords error => %v", err)
+	}
+
+	if _, err := p.SyncRecords(context.TODO(), "example.com", []libdns.Record{{Type: "A", Name: "www", Value: "192.0.2.1"}, {Type: "A", Name: "mail", Value: "192.0.2.2"}}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_ValidationError(t *testing.T) {
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/zones":
+			fmt.Fprint(w, `{"zones":[{"id":"z","name":"example.com"}]}`)
+		case r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			fmt.Fprint(w, `{"record":{},"error":{"message":"422 Unprocessable Entity: invalid TTL","code":422}}`)
+		default:
+			fmt.Fprint(w, `{"records":[]}`)
+		}
+	})
+
+	_, err := p.AppendRecords(context.TODO(), "example.com", []libdns.Record{{Type: "TXT", Name: "test", Value: "v", TTL: time.Hour}})
+	var validationErr *hetzner.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("err is not a ValidationError => %v", err)
+	}
+	if validationErr.Field != "ttl" || validationErr.Message != "422 Unprocessable Entity: invalid TTL" || validationErr.Record.Name != "test" {
+		t.Fatalf("unexpected ValidationError => %+v", validationErr)
+	}
+	var apiErr *hetzner.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnprocessableEntity || apiErr.Path != "/records" {
+		t.Fatalf("unexpected APIError => %+v", apiErr)
+	}
+}
+
+func Test_CreateRecovery(t *testing.T) {
+	api := &fakeAPI{zones: []fakeZone{{ID: "z", Name: "example.com", TTL: 86400}}}
+	var posts atomic.Int32
+	status := http.StatusConflict
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/records" && r.Method == http.MethodPost && posts.Add(1) == 1 {
+			// another client created the same record just before
+			api.mu.Lock()
+			api.addRecord("z", "A www 192.0.2.1")
+			api.mu.Unlock()
+			w.WriteHeader(status)
+			return
+		}
+		api.ServeHTTP(w, r)
+	})
+
+	added, err := p.AppendRecords(context.TODO(), "example.com", []libdns.Record{{Type: "A", Name: "www", Value: "192.0.2.1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(added) != 1 || added[0].ID != "r1" || posts.Load() != 1 {
+		t.Fatalf("conflict not recovered from the existing record => %+v, %d creates", added, posts.Load())
+	}
+
+	posts.Store(0)
+	status = http.StatusUnprocessableEntity
+	_, err = p.AppendRecords(context.TODO(), "example.com", []libdns.Record{{Type: "A", Name: "mail", Value: "192.0.2.2"}})
+	if !hasStatus(err, http.StatusUnprocessableEntity) || posts.Load() != 1 {
+		t.Fatalf("422 was recovered from => %v, %d creates", err, posts.Load())
+	}
+}
+
+func Test_UpdateRecovery(t *testing.T) {
+	p, api := newFakeProvider(t, "A www 192.0.2.1")
+
+	updated, err := p.SetRecords(context.TODO(), "example.com", []libdns.Record{{ID: "stale", Type: "A", Name: "www", Value: "192.0.2.2"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updated) != 1 || updated[0].ID != "r1" {
+		t.Fatalf("stale ID not resolved by name and type => %+v", updated)
+	}
+
+	_, err = p.SetRecords(context.TODO(), "example.com", []libdns.Record{{ID: "stale", Type: "A", Name: "mail", Value: "192.0.2.3"}})
+	if !errors.Is(err, hetzner.ErrRecordNotFound) {
+		t.Fatalf("err != ErrRecordNotFound => %v", err)
+	}
+	if records := api.dump("z"); !slices.Equal(records, []string{"A www 192.0.2.2"}) {
+		t.Fatalf("deleted record was recreated => %v", records)
+	}
+}
+
+// hasStatus reports whether err is an *hetzner.APIError with the given status.
+func hasStatus(err error, status int) bool {
+	var apiErr *hetzner.APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == status
+}
+
+// slowRecords serves example.com, answering record requests only after
+// delay or when the request is canceled.
+func slowRecords(delay time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/zones" {
+			fmt.Fprint(w, `{"zones":[{"id":"z","name":"example.com"}]}`)
+			return
+		}
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, `{"records":[]}`)
+		default:
+			fmt.Fprint(w, testRecordResponse)
+		}
+	}
+}
+
+func Test_OperationTimeouts(t *testing.T) {
+	p := newTestProvider(t, slowRecords(200*time.Millisecond))
+	p.ReadTimeout = 20 * time.Millisecond
+	ctx, cancel := context.WithTimeout(hetzner.WithNoRetry(context.TODO()), 10*time.Second)
+	defer cancel()
+
+	if _, err := p.GetRecords(ctx, "example.com"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err != context.DeadlineExceeded => %v", err)
+	}
+
+	// reads and writes are bounded independently
+	p.WriteTimeout = time.Second
+	if _, err := p.AppendRecords(ctx, "example.com", []libdns.Record{{Type: "TXT", Name: "test", Value: "test"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.GetRecords(hetzner.WithTimeout(ctx, time.Second), "example.com"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_DefaultRequestTimeout(t *testing.T) {
+	p := newTestProvider(t, slowRecords(200*time.Millisecond))
+	p.DefaultRequestTimeout = 20 * time.Millisecond
+	ctx := hetzner.WithNoRetry(context.Background())
+
+	if _, err := p.GetRecords(ctx, "example.com"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err != context.DeadlineExceeded => %v", err)
+	}
+
+	// the caller's deadline takes precedence
+	withDeadline, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if _, err := p.GetRecords(withDeadline, "example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	p.DefaultRequestTimeout = -1
+	if _, err := p.GetRecords(ctx, "example.com"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_GetRecordsOrder(t *testing.T) {
+	p, _ := newFakeProvider(t,
+		"TXT www b",
+		"A www 192.0.2.2",
+		"A api 192.0.2.1",
+		"TXT www a",
+		"A www 192.0.2.2",
+	)
+
+	records, err := p.GetRecords(context.TODO(), "example.com.")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var order []string
+	for _, r := range records {
+		order = append(order, r.ID+" "+r.Type+" "+r.Name+" "+r.Value)
+	}
+	expected := []string{
+		"r3 A api 192.0.2.1",
+		"r2 A www 192.0.2.2",
+		"r5 A www 192.0.2.2",
+		"r4 TXT www a",
+		"r1 TXT www b",
+	}
+	if strings.Join(order, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("order != expected =>\n%s", strings.Join(order, "\n"))
+	}
+}
+
+func Test_CompareAndSwapRecord(t *testing.T) {
+	p, api := newFakeProvider(t, "TXT test old")
+	api.addZone("example.org", "TXT test old")
+
+	_, err := p.CompareAndSwapRecord(context.TODO(), "example.com", "other", libdns.Record{ID: "r1", Type: "TXT", Name: "test", Value: "new"})
+	if !errors.Is(err, hetzner.ErrConflict) {
+		t.Fatalf("err != ErrConflict => %v", err)
+	}
+	_, err = p.CompareAndSwapRecord(context.TODO(), "example.com", "other", libdns.Record{Type: "TXT", Name: "test", Value: "new"})
+	if !errors.Is(err, hetzner.ErrConflict) {
+		t.Fatalf("err != ErrConflict => %v", err)
+	}
+
+	// r2 belongs to example.org
+	_, err = p.CompareAndSwapRecord(context.TODO(), "example.com", "old", libdns.Record{ID: "r2", Type: "TXT", Name: "test", Value: "new"})
+	if !errors.Is(err, hetzner.ErrRecordNotFound) {
+		t.Fatalf("err != ErrRecordNotFound => %v", err)
+	}
+
+	record, err := p.CompareAndSwapRecord(context.TODO(), "example.com", "old", libdns.Record{Type: "TXT", Name: "test", Value: "new"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.ID != "r1" || record.Value != "new" {
+		t.Fatalf("unexpected record => %+v", record)
+	}
+	if records := api.dump("z2"); len(records) != 1 || records[0] != "TXT test old" {
+		t.Fatalf("unexpected records in example.org => %v", records)
+	}
+}
@@ -0,0 +1,93 @@
+// Command externaldns-hetzner-webhook is an external-dns webhook provider
+// backed by Hetzner DNS.
+//
+// It is configured through environment variables:
+//
+//	HETZNER_API_TOKEN  Hetzner DNS Auth-API-Token (required)
+//	DOMAIN_FILTER      comma-separated list of zones to manage (required)
+//	WEBHOOK_ADDR       address of the webhook API (default "localhost:8888")
+//	HEALTH_ADDR        address of the health endpoint (default ":8080")
+//
+// Run it as a sidecar of external-dns started with --provider=webhook.
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/libdns/hetzner"
+)
+
+func main() {
+	token := os.Getenv("HETZNER_API_TOKEN")
+	if token == "" {
+		log.Fatal("HETZNER_API_TOKEN not set")
+	}
+
+	var zones []string
+	for _, zone := range strings.Split(os.Getenv("DOMAIN_FILTER"), ",") {
+		if zone = strings.TrimSpace(zone); zone != "" {
+			zones = append(zones, strings.TrimSuffix(zone, "."))
+		}
+	}
+	if len(zones) == 0 {
+		log.Fatal("DOMAIN_FILTER not set")
+	}
+
+	w := &webhook{
+		provider: &hetzner.Provider{AuthAPIToken: token, UserAgent: "externaldns-hetzner-webhook"},
+		zones:    zones,
+	}
+
+	webhookServer := &http.Server{
+		Addr:              envOrDefault("WEBHOOK_ADDR", "localhost:8888"),
+		Handler:           w.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	healthServer := &http.Server{
+		Addr:              envOrDefault("HEALTH_ADDR", ":8080"),
+		Handler:           healthHandler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	for _, server := range []*http.Server{webhookServer, healthServer} {
+		go func(server *http.Server) {
+			log.Printf("listening on %s", server.Addr)
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Fatal(err)
+			}
+		}(server)
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	<-signals
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_ = webhookServer.Shutdown(ctx)
+	_ = healthServer.Shutdown(ctx)
+}
+
+func envOrDefault(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func healthHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	})
+	return mux
+}
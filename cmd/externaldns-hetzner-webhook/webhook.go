@@ -0,0 +1,294 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// mediaType is the content type of the external-dns webhook protocol.
+const mediaType = "application/external.dns.webhook+json;version=1"
+
+// managedTypes are the record types exposed to external-dns.
+var managedTypes = map[string]bool{
+	"A": true, "AAAA": true, "CNAME": true, "TXT": true,
+	"MX": true, "SRV": true, "NS": true, "CAA": true,
+}
+
+// endpoint mirrors external-dns' endpoint.Endpoint.
+type endpoint struct {
+	DNSName          string            `json:"dnsName"`
+	Targets          []string          `json:"targets"`
+	RecordType       string            `json:"recordType"`
+	SetIdentifier    string            `json:"setIdentifier,omitempty"`
+	RecordTTL        int64             `json:"recordTTL,omitempty"`
+	Labels           map[string]string `json:"labels,omitempty"`
+	ProviderSpecific []property        `json:"providerSpecific,omitempty"`
+}
+
+type property struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// changes mirrors external-dns' plan.Changes.
+type changes struct {
+	Create    []*endpoint `json:"Create"`
+	UpdateOld []*endpoint `json:"UpdateOld"`
+	UpdateNew []*endpoint `json:"UpdateNew"`
+	Delete    []*endpoint `json:"Delete"`
+}
+
+// domainFilter mirrors external-dns' endpoint.DomainFilter.
+type domainFilter struct {
+	Include []string `json:"include"`
+}
+
+type recordProvider interface {
+	libdns.RecordGetter
+	libdns.RecordAppender
+	libdns.RecordSetter
+	libdns.RecordDeleter
+}
+
+type webhook struct {
+	provider recordProvider
+	zones    []string
+}
+
+func (wh *webhook) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", wh.negotiate)
+	mux.HandleFunc("/records", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			wh.getRecords(w, r)
+		case http.MethodPost:
+			wh.applyChanges(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	mux.HandleFunc("/adjustendpoints", wh.adjustEndpoints)
+	return mux
+}
+
+func (wh *webhook) negotiate(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" || r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	writeJSON(w, domainFilter{Include: wh.zones})
+}
+
+func (wh *webhook) getRecords(w http.ResponseWriter, r *http.Request) {
+	var endpoints []*endpoint
+	for _, zone := range wh.zones {
+		records, err := wh.provider.GetRecords(r.Context(), zone)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		endpoints = append(endpoints, toEndpoints(zone, records)...)
+	}
+	if endpoints == nil {
+		endpoints = []*endpoint{}
+	}
+	writeJSON(w, endpoints)
+}
+
+func (wh *webhook) adjustEndpoints(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var endpoints []*endpoint
+	if err := json.NewDecoder(r.Body).Decode(&endpoints); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	for _, e := range endpoints {
+		e.DNSName = strings.ToLower(strings.TrimSuffix(e.DNSName, "."))
+	}
+	writeJSON(w, endpoints)
+}
+
+func (wh *webhook) applyChanges(w http.ResponseWriter, r *http.Request) {
+	var c changes
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	for _, e := range c.Delete {
+		if err := wh.setRRset(ctx, e, nil); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+	for i, e := range c.UpdateNew {
+		var old *endpoint
+		if i < len(c.UpdateOld) {
+			old = c.UpdateOld[i]
+		}
+		if err := wh.setRRset(ctx, old, e); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+	for _, e := range c.Create {
+		if err := wh.setRRset(ctx, nil, e); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// setRRset converges the RRset of old (or desired, if old is nil) to the
+// targets of desired. A nil desired deletes the targets of old.
+func (wh *webhook) setRRset(ctx context.Context, old *endpoint, desired *endpoint) error {
+	ref := desired
+	if ref == nil {
+		ref = old
+	}
+	zone, name, ok := wh.zoneFor(ref.DNSName)
+	if !ok {
+		log.Printf("skipping %s: not in any managed zone", ref.DNSName)
+		return nil
+	}
+
+	records, err := wh.provider.GetRecords(ctx, zone)
+	if err != nil {
+		return err
+	}
+
+	existing := map[string]libdns.Record{}
+	for _, record := range records {
+		if strings.EqualFold(record.Name, name) && record.Type == ref.RecordType {
+			existing[record.Value] = record
+		}
+	}
+
+	if desired == nil {
+		var toDelete []libdns.Record
+		for _, target := range old.Targets {
+			if record, ok := existing[target]; ok {
+				toDelete = append(toDelete, record)
+			}
+		}
+		_, err := wh.provider.DeleteRecords(ctx, zone, toDelete)
+		return err
+	}
+
+	ttl := time.Duration(desired.RecordTTL) * time.Second
+	wanted := map[string]bool{}
+	var toAppend, toSet []libdns.Record
+	for _, target := range desired.Targets {
+		wanted[target] = true
+		record, ok := existing[target]
+		if !ok {
+			toAppend = append(toAppend, libdns.Record{Type: desired.RecordType, Name: name, Value: target, TTL: ttl})
+		} else if ttl != 0 && record.TTL != ttl {
+			record.TTL = ttl
+			toSet = append(toSet, record)
+		}
+	}
+	var toDelete []libdns.Record
+	for value, record := range existing {
+		if !wanted[value] {
+			toDelete = append(toDelete, record)
+		}
+	}
+
+	if len(toAppend) > 0 {
+		if _, err := wh.provider.AppendRecords(ctx, zone, toAppend); err != nil {
+			return err
+		}
+	}
+	if len(toSet) > 0 {
+		if _, err := wh.provider.SetRecords(ctx, zone, toSet); err != nil {
+			return err
+		}
+	}
+	if len(toDelete) > 0 {
+		if _, err := wh.provider.DeleteRecords(ctx, zone, toDelete); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// zoneFor returns the most specific managed zone containing dnsName and the
+// name relative to it.
+func (wh *webhook) zoneFor(dnsName string) (string, string, bool) {
+	dnsName = strings.ToLower(strings.TrimSuffix(dnsName, "."))
+	best := ""
+	for _, zone := range wh.zones {
+		if (dnsName == zone || strings.HasSuffix(dnsName, "."+zone)) && len(zone) > len(best) {
+			best = zone
+		}
+	}
+	if best == "" {
+		return "", "", false
+	}
+	if dnsName == best {
+		return best, "@", true
+	}
+	return best, strings.TrimSuffix(dnsName, "."+best), true
+}
+
+// toEndpoints groups the zone's records into one endpoint per RRset.
+func toEndpoints(zone string, records []libdns.Record) []*endpoint {
+	byKey := map[string]*endpoint{}
+	var keys []string
+	for _, record := range records {
+		if !managedTypes[record.Type] || (record.Type == "NS" && record.Name == "@") {
+			continue
+		}
+		dnsName := zone
+		if record.Name != "@" && record.Name != "" {
+			dnsName = record.Name + "." + zone
+		}
+		key := dnsName + " " + record.Type
+		e, ok := byKey[key]
+		if !ok {
+			e = &endpoint{
+				DNSName:    dnsName,
+				RecordType: record.Type,
+				RecordTTL:  int64(record.TTL.Seconds()),
+			}
+			byKey[key] = e
+			keys = append(keys, key)
+		}
+		e.Targets = append(e.Targets, record.Value)
+	}
+
+	sort.Strings(keys)
+	endpoints := make([]*endpoint, 0, len(keys))
+	for _, key := range keys {
+		endpoints = append(endpoints, byKey[key])
+	}
+	return endpoints
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", mediaType)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("writing response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	log.Printf("error: %v", err)
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
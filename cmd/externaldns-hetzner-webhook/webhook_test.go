@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// memoryProvider is an in-memory recordProvider for a single zone.
+type memoryProvider struct {
+	records []libdns.Record
+	nextID  int
+}
+
+func (m *memoryProvider) GetRecords(_ context.Context, _ string) ([]libdns.Record, error) {
+	return append([]libdns.Record(nil), m.records...), nil
+}
+
+func (m *memoryProvider) AppendRecords(_ context.Context, _ string, records []libdns.Record) ([]libdns.Record, error) {
+	for i := range records {
+		m.nextID++
+		records[i].ID = strconv.Itoa(m.nextID)
+		m.records = append(m.records, records[i])
+	}
+	return records, nil
+}
+
+func (m *memoryProvider) SetRecords(_ context.Context, _ string, records []libdns.Record) ([]libdns.Record, error) {
+	for _, record := range records {
+		for i := range m.records {
+			if m.records[i].ID == record.ID {
+				m.records[i] = record
+			}
+		}
+	}
+	return records, nil
+}
+
+func (m *memoryProvider) DeleteRecords(_ context.Context, _ string, records []libdns.Record) ([]libdns.Record, error) {
+	for _, record := range records {
+		for i := range m.records {
+			if m.records[i].ID == record.ID {
+				m.records = append(m.records[:i], m.records[i+1:]...)
+				break
+			}
+		}
+	}
+	return records, nil
+}
+
+func Test_Webhook(t *testing.T) {
+	provider := &memoryProvider{}
+	provider.AppendRecords(context.TODO(), "example.com", []libdns.Record{
+		{Type: "NS", Name: "@", Value: "hydrogen.ns.hetzner.com.", TTL: time.Hour},
+		{Type: "A", Name: "www", Value: "192.0.2.1", TTL: time.Hour},
+		{Type: "A", Name: "old", Value: "192.0.2.9", TTL: time.Hour},
+	})
+	server := httptest.NewServer((&webhook{provider: provider, zones: []string{"example.com"}}).handler())
+	defer server.Close()
+
+	body := `{
+		"Create": [{"dnsName": "new.example.com", "targets": ["192.0.2.5"], "recordType": "A", "recordTTL": 60}],
+		"UpdateOld": [{"dnsName": "www.example.com", "targets": ["192.0.2.1"], "recordType": "A"}],
+		"UpdateNew": [{"dnsName": "www.example.com", "targets": ["192.0.2.2", "192.0.2.3"], "recordType": "A", "recordTTL": 300}],
+		"Delete": [{"dnsName": "old.example.com", "targets": ["192.0.2.9"], "recordType": "A"}]
+	}`
+	response, err := http.Post(server.URL+"/records", mediaType, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusNoContent {
+		t.Fatalf("response.StatusCode != 204 => %d", response.StatusCode)
+	}
+
+	endpoints := toEndpoints("example.com", provider.records)
+	expected := []endpoint{
+		{DNSName: "new.example.com", RecordType: "A", Targets: []string{"192.0.2.5"}, RecordTTL: 60},
+		{DNSName: "www.example.com", RecordType: "A", Targets: []string{"192.0.2.2", "192.0.2.3"}, RecordTTL: 300},
+	}
+	if len(endpoints) != len(expected) {
+		t.Fatalf("len(endpoints) != len(expected) => %d != %d", len(endpoints), len(expected))
+	}
+	for i, e := range expected {
+		got := endpoints[i]
+		if got.DNSName != e.DNSName || got.RecordType != e.RecordType || got.RecordTTL != e.RecordTTL || strings.Join(got.Targets, ",") != strings.Join(e.Targets, ",") {
+			t.Fatalf("endpoints[%d] != expected[%d] => %+v != %+v", i, i, *got, e)
+		}
+	}
+}
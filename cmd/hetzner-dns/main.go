@@ -0,0 +1,275 @@
+// Command hetzner-dns is a command line client for Hetzner DNS built on the
+// same code path as the libdns provider.
+//
+// Usage:
+//
+//	hetzner-dns [-token TOKEN] <command> [arguments]
+//
+// Commands:
+//
+//	zones                                       list zones
+//	records <zone>                              list the records of a zone
+//	create <zone> <name> <type> <value> [ttl]   create a record
+//	update <zone> <id> <name> <type> <value> [ttl]
+//	                                            update a record
+//	delete <zone> <id>...                       delete records
+//	export <zone>                               write the zone as a BIND zone file to stdout
+//	import <zone> <file>                        add the records of a zone file,
+//	                                            except its SOA and apex NS records
+//	sync [-dry-run] <zone> <file>               reconcile the zone to a zone file
+//
+// The token defaults to the HETZNER_API_TOKEN environment variable. TTLs
+// are given in seconds.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/libdns/hetzner"
+	"github.com/libdns/libdns"
+)
+
+var errUsage = errors.New("usage: hetzner-dns [-token TOKEN] zones|records|create|update|delete|export|import|sync [arguments]")
+
+func main() {
+	token := flag.String("token", os.Getenv("HETZNER_API_TOKEN"), "Hetzner DNS Auth-API-Token")
+	flag.Parse()
+
+	if *token == "" {
+		fmt.Fprintln(os.Stderr, "no token given, use -token or HETZNER_API_TOKEN")
+		os.Exit(2)
+	}
+
+	p := &hetzner.Provider{AuthAPIToken: *token, UserAgent: "hetzner-dns"}
+	if err := run(context.Background(), p, flag.Args(), os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		if err == errUsage {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, p *hetzner.Provider, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	command, args := args[0], args[1:]
+	switch command {
+	case "zones":
+		return listZones(ctx, p, out)
+	case "records":
+		if len(args) != 1 {
+			return errUsage
+		}
+		return listRecords(ctx, p, args[0], out)
+	case "create":
+		if len(args) != 4 && len(args) != 5 {
+			return errUsage
+		}
+		record, err := recordFromArgs("", args[1:])
+		if err != nil {
+			return err
+		}
+		records, err := p.AppendRecords(ctx, args[0], []libdns.Record{record})
+		if err != nil {
+			return err
+		}
+		return printRecords(out, records)
+	case "update":
+		if len(args) != 5 && len(args) != 6 {
+			return errUsage
+		}
+		record, err := recordFromArgs(args[1], args[2:])
+		if err != nil {
+			return err
+		}
+		records, err := p.SetRecords(ctx, args[0], []libdns.Record{record})
+		if err != nil {
+			return err
+		}
+		return printRecords(out, records)
+	case "delete":
+		if len(args) < 2 {
+			return errUsage
+		}
+		var records []libdns.Record
+		for _, id := range args[1:] {
+			records = append(records, libdns.Record{ID: id})
+		}
+		_, err := p.DeleteRecords(ctx, args[0], records)
+		return err
+	case "export":
+		if len(args) != 1 {
+			return errUsage
+		}
+		return exportZone(ctx, p, args[0], out)
+	case "import":
+		if len(args) != 2 {
+			return errUsage
+		}
+		records, err := readZoneFile(args[0], args[1])
+		if err != nil {
+			return err
+		}
+		records, err = p.AppendRecords(ctx, args[0], withoutApexNSAndSOA(records))
+		if err != nil {
+			return err
+		}
+		return printRecords(out, records)
+	case "sync":
+		return syncZone(ctx, p, args, out)
+	default:
+		return errUsage
+	}
+}
+
+func listZones(ctx context.Context, p *hetzner.Provider, out io.Writer) error {
+	zones, err := p.ListZones(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tTTL\tRECORDS")
+	for _, zone := range zones {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\n", zone.ID, zone.Name, int(zone.TTL.Seconds()), zone.RecordsCount)
+	}
+	return w.Flush()
+}
+
+func listRecords(ctx context.Context, p *hetzner.Provider, zone string, out io.Writer) error {
+	records, err := p.GetRecords(ctx, zone)
+	if err != nil {
+		return err
+	}
+	return printRecords(out, records)
+}
+
+func printRecords(out io.Writer, records []libdns.Record) error {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tTYPE\tTTL\tVALUE")
+	for _, r := range records {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", r.ID, r.Name, r.Type, int(r.TTL.Seconds()), r.Value)
+	}
+	return w.Flush()
+}
+
+// recordFromArgs builds a record from name, type, value and optional TTL
+// arguments.
+func recordFromArgs(id string, args []string) (libdns.Record, error) {
+	record := libdns.Record{
+		ID:    id,
+		Name:  args[0],
+		Type:  strings.ToUpper(args[1]),
+		Value: args[2],
+	}
+	if len(args) > 3 {
+		ttl, err := strconv.Atoi(args[3])
+		if err != nil {
+			return libdns.Record{}, fmt.Errorf("invalid TTL %q", args[3])
+		}
+		record.TTL = time.Duration(ttl) * time.Second
+	}
+	return record, nil
+}
+
+func exportZone(ctx context.Context, p *hetzner.Provider, zone string, out io.Writer) error {
+	meta := hetzner.ZoneMetadata{Origin: zone}
+
+	zones, err := p.ListZones(ctx)
+	if err != nil {
+		return err
+	}
+	for _, z := range zones {
+		if z.Name == strings.TrimSuffix(zone, ".") {
+			meta.TTL = z.TTL
+		}
+	}
+
+	records, err := p.GetRecords(ctx, zone)
+	if err != nil {
+		return err
+	}
+	return hetzner.WriteZoneFile(out, meta, records)
+}
+
+func readZoneFile(zone string, path string) ([]libdns.Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return hetzner.ParseZoneFile(f, zone)
+}
+
+// withoutApexNSAndSOA drops the SOA and apex NS records of a parsed zone
+// file. Hetzner maintains them for every zone, so appending the exported
+// ones would duplicate them.
+func withoutApexNSAndSOA(records []libdns.Record) []libdns.Record {
+	var result []libdns.Record
+	for _, r := range records {
+		if r.Type == "SOA" || (r.Type == "NS" && r.Name == "@") {
+			continue
+		}
+		result = append(result, r)
+	}
+	return result
+}
+
+func syncZone(ctx context.Context, p *hetzner.Provider, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("sync", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "only print the changes")
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+	if flags.NArg() != 2 {
+		return errUsage
+	}
+	zone := flags.Arg(0)
+
+	desired, err := readZoneFile(zone, flags.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	var plan hetzner.Plan
+	if *dryRun {
+		plan, err = p.PlanSync(ctx, zone, desired)
+	} else {
+		plan, err = p.SyncRecords(ctx, zone, desired)
+	}
+	if err != nil {
+		return err
+	}
+
+	printPlan(out, plan)
+	return nil
+}
+
+func printPlan(out io.Writer, plan hetzner.Plan) {
+	if plan.Empty() {
+		fmt.Fprintln(out, "no changes")
+		return
+	}
+	for _, r := range plan.Create {
+		fmt.Fprintf(out, "+ %s %d %s %s\n", r.Name, int(r.TTL.Seconds()), r.Type, r.Value)
+	}
+	for _, r := range plan.Update {
+		fmt.Fprintf(out, "~ %s %d %s %s (%s)\n", r.Name, int(r.TTL.Seconds()), r.Type, r.Value, r.ID)
+	}
+	for _, r := range plan.Delete {
+		fmt.Fprintf(out, "- %s %d %s %s (%s)\n", r.Name, int(r.TTL.Seconds()), r.Type, r.Value, r.ID)
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/libdns/hetzner"
+)
+
+type apiRecord struct {
+	ID     string `json:"id"`
+	ZoneID string `json:"zone_id"`
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	TTL    *int   `json:"ttl,omitempty"`
+}
+
+// fakeAPI serves the zone "example.com" with ID "z" from memory.
+type fakeAPI struct {
+	mu      sync.Mutex
+	records []apiRecord
+	nextID  int
+}
+
+func (api *fakeAPI) add(r apiRecord) apiRecord {
+	api.nextID++
+	r.ID = fmt.Sprintf("r%d", api.nextID)
+	r.ZoneID = "z"
+	api.records = append(api.records, r)
+	return r
+}
+
+// dump returns the records as "<type> <name> <ttl> <value>", sorted.
+func (api *fakeAPI) dump() []string {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	var records []string
+	for _, r := range api.records {
+		ttl := 0
+		if r.TTL != nil {
+			ttl = *r.TTL
+		}
+		records = append(records, fmt.Sprintf("%s %s %d %s", r.Type, r.Name, ttl, r.Value))
+	}
+	slices.Sort(records)
+	return records
+}
+
+func (api *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	switch {
+	case r.URL.Path == "/zones" && r.Method == http.MethodGet:
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"zones": []map[string]interface{}{{"id": "z", "name": "example.com", "ttl": 86400, "records_count": len(api.records)}},
+			"meta":  map[string]interface{}{"pagination": map[string]int{"page": 1, "last_page": 1}},
+		})
+	case r.URL.Path == "/records" && r.Method == http.MethodGet:
+		json.NewEncoder(w).Encode(map[string]interface{}{"records": api.records})
+	case r.URL.Path == "/records" && r.Method == http.MethodPost:
+		var record apiRecord
+		json.NewDecoder(r.Body).Decode(&record)
+		json.NewEncoder(w).Encode(map[string]interface{}{"record": api.add(record)})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func ttl(seconds int) *int {
+	return &seconds
+}
+
+func Test_ExportImport(t *testing.T) {
+	api := &fakeAPI{}
+	apex := []apiRecord{
+		{Type: "SOA", Name: "@", Value: "hydrogen.ns.hetzner.com. dns.hetzner.com. 2020080302 86400 10800 3600000 3600"},
+		{Type: "NS", Name: "@", Value: "hydrogen.ns.hetzner.com."},
+	}
+	for _, r := range apex {
+		api.add(r)
+	}
+	api.add(apiRecord{Type: "A", Name: "www", Value: "192.0.2.1", TTL: ttl(300)})
+	api.add(apiRecord{Type: "NS", Name: "sub", Value: "ns.example.net."})
+	api.add(apiRecord{Type: "TXT", Name: "mail._domainkey", Value: "v=DKIM1; p=" + strings.Repeat("a", 300), TTL: ttl(3600)})
+	expected := api.dump()
+
+	server := httptest.NewServer(api)
+	defer server.Close()
+	p := hetzner.New("token", hetzner.WithBaseURL(server.URL))
+
+	var zoneFile strings.Builder
+	if err := run(context.TODO(), p, []string{"export", "example.com"}, &zoneFile); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "example.com.zone")
+	if err := os.WriteFile(path, []byte(zoneFile.String()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// start over from a fresh zone, which has only the records Hetzner
+	// creates itself
+	api.mu.Lock()
+	api.records = nil
+	for _, r := range apex {
+		api.add(r)
+	}
+	api.mu.Unlock()
+
+	var out strings.Builder
+	if err := run(context.TODO(), p, []string{"import", "example.com", path}, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	// the exported records without a TTL inherit the zone's
+	records := api.dump()
+	expected = slices.DeleteFunc(expected, func(r string) bool { return strings.HasPrefix(r, "NS sub ") })
+	expected = append(expected, "NS sub 86400 ns.example.net.")
+	slices.Sort(expected)
+	if strings.Join(records, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("records != expected =>\n%s\n!=\n%s", strings.Join(records, "\n"), strings.Join(expected, "\n"))
+	}
+}
@@ -0,0 +1,24 @@
+package hetzner
+
+import "sync"
+
+// forEachConcurrently calls fn for every index in [0, n) with at most
+// concurrency calls running at the same time, and waits for all of them.
+func forEachConcurrently(n int, concurrency int, fn func(i int)) {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
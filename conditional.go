@@ -0,0 +1,98 @@
+package hetzner
+
+import (
+	"maps"
+	"net/http"
+	"sync"
+)
+
+// conditionalCache remembers the validators and bodies of GET responses, so
+// repeated requests can be sent as conditional requests and answered with
+// 304 Not Modified if nothing changed.
+type conditionalCache struct {
+	mu      sync.Mutex
+	entries map[string]conditionalEntry
+}
+
+type conditionalEntry struct {
+	etag         string
+	lastModified string
+	body         []byte
+}
+
+func newConditionalCache() *conditionalCache {
+	return &conditionalCache{entries: map[string]conditionalEntry{}}
+}
+
+// prepare adds the validators of a previous response for path to the
+// request.
+func (c *conditionalCache) prepare(request *http.Request, path string) {
+	if c == nil || request.Method != http.MethodGet {
+		return
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[path]
+	c.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	if entry.etag != "" {
+		request.Header.Set("If-None-Match", entry.etag)
+	}
+	if entry.lastModified != "" {
+		request.Header.Set("If-Modified-Since", entry.lastModified)
+	}
+}
+
+// store remembers the response for path if it carries validators.
+func (c *conditionalCache) store(path string, header http.Header, body []byte) {
+	if c == nil {
+		return
+	}
+
+	entry := conditionalEntry{etag: header.Get("ETag"), lastModified: header.Get("Last-Modified"), body: body}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry.etag == "" && entry.lastModified == "" {
+		delete(c.entries, path)
+		return
+	}
+	c.entries[path] = entry
+}
+
+// cached returns the body of the previous response for path.
+func (c *conditionalCache) cached(path string) ([]byte, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[path]
+	return entry.body, ok
+}
+
+// deleteFunc forgets the responses for all paths for which del returns true.
+func (c *conditionalCache) deleteFunc(del func(path string) bool) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	maps.DeleteFunc(c.entries, func(path string, _ conditionalEntry) bool { return del(path) })
+}
+
+// WithConditionalRequests makes the provider remember the ETag and
+// Last-Modified validators of GET responses, like zone and record listings,
+// and send repeated requests as conditional requests. If the API answers
+// with 304 Not Modified, the remembered response is used, which makes
+// polling unchanged zones cheap. The bodies of responses with validators are
+// kept in memory.
+func WithConditionalRequests() Option {
+	return func(p *Provider) {
+		p.conditional = newConditionalCache()
+	}
+}
@@ -0,0 +1,147 @@
+package hetzner_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libdns/hetzner"
+	"github.com/libdns/libdns"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata/contract")
+
+// contractRequest is a request as written to the golden files.
+type contractRequest struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// contractHandler answers like the API would and records the requests.
+type contractHandler struct {
+	mu       sync.Mutex
+	requests []contractRequest
+}
+
+func (h *contractHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	h.mu.Lock()
+	request := contractRequest{Method: r.Method, Path: r.URL.RequestURI()}
+	if len(body) > 0 {
+		request.Body = body
+	}
+	h.requests = append(h.requests, request)
+	h.mu.Unlock()
+
+	switch {
+	case r.URL.Path == "/zones":
+		fmt.Fprint(w, `{"zones":[{"id":"z","name":"example.com","ttl":86400}],"meta":{"pagination":{"page":1,"last_page":1}}}`)
+	case r.URL.Path == "/records" && r.Method == http.MethodGet:
+		fmt.Fprint(w, `{"records":[{"id":"r1","zone_id":"z","type":"A","name":"www","value":"192.0.2.1","ttl":300}]}`)
+	case r.URL.Path == "/records/r1" && r.Method == http.MethodGet:
+		fmt.Fprint(w, `{"record":{"id":"r1","zone_id":"z","type":"A","name":"www","value":"192.0.2.1","ttl":300}}`)
+	case r.URL.Path == "/records/bulk":
+		fmt.Fprintf(w, `%s`, bytes.TrimSpace(body))
+	case r.Method == http.MethodPost || r.Method == http.MethodPut:
+		var record map[string]interface{}
+		json.Unmarshal(body, &record)
+		record["id"] = "r1"
+		json.NewEncoder(w).Encode(map[string]interface{}{"record": record})
+	}
+}
+
+// Test_Contract checks the requests every operation sends against the
+// golden files in testdata/contract, so changes to the wire format don't go
+// unnoticed. Run with -update to rewrite them after an intended change.
+func Test_Contract(t *testing.T) {
+	testCases := []struct {
+		name string
+		run  func(p *hetzner.Provider) error
+	}{
+		{"append_a", appendRecord(libdns.Record{Type: "A", Name: "www", Value: "192.0.2.1", TTL: 5 * time.Minute})},
+		{"append_aaaa", appendRecord(libdns.Record{Type: "AAAA", Name: "www", Value: "2001:0db8::0001"})},
+		{"append_cname", appendRecord(libdns.Record{Type: "CNAME", Name: "blog", Value: "example.org."})},
+		{"append_mx", appendRecord(libdns.Record{Type: "MX", Name: "@", Value: "10 mail.example.com."})},
+		{"append_ns", appendRecord(libdns.Record{Type: "NS", Name: "sub", Value: "ns1.example.org."})},
+		{"append_srv", appendRecord(libdns.Record{Type: "SRV", Name: "_sip._tcp", Value: "10 5 5060 sip.example.com."})},
+		{"append_txt", appendRecord(libdns.Record{Type: "TXT", Name: "_acme-challenge.www.example.com.", Value: "token", TTL: hetzner.InheritZoneTTL})},
+		{"append_caa", appendRecord(libdns.Record{Type: "CAA", Name: "@", Value: `0 issue "letsencrypt.org"`, TTL: time.Hour})},
+		{"append_tlsa", appendRecord(libdns.Record{Type: "TLSA", Name: "_443._tcp", Value: "3 1 1 0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"})},
+		{"append_ds", appendRecord(libdns.Record{Type: "DS", Name: "sub", Value: "12345 13 2 0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"})},
+		{"set_existing", func(p *hetzner.Provider) error {
+			_, err := p.SetRecords(context.TODO(), "example.com", []libdns.Record{{Type: "A", Name: "www", Value: "192.0.2.2"}})
+			return err
+		}},
+		{"set_by_id", func(p *hetzner.Provider) error {
+			_, err := p.SetRecords(context.TODO(), "example.com", []libdns.Record{{ID: "r1", Type: "A", Name: "www", Value: "192.0.2.2", TTL: time.Minute}})
+			return err
+		}},
+		{"delete_by_id", func(p *hetzner.Provider) error {
+			_, err := p.DeleteRecords(context.TODO(), "example.com", []libdns.Record{{ID: "r1", Type: "A", Name: "www", Value: "192.0.2.1"}})
+			return err
+		}},
+		{"delete_by_value", func(p *hetzner.Provider) error {
+			_, err := p.DeleteRecords(context.TODO(), "example.com", []libdns.Record{{Type: "A", Name: "www", Value: "192.0.2.1"}})
+			return err
+		}},
+		{"get_records", func(p *hetzner.Provider) error {
+			_, err := p.GetRecords(context.TODO(), "example.com")
+			return err
+		}},
+		{"bulk_ttl", func(p *hetzner.Provider) error {
+			_, err := p.SetZoneRecordTTLs(context.TODO(), "example.com", time.Hour, nil)
+			return err
+		}},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			handler := &contractHandler{}
+			p := newTestProvider(t, handler.ServeHTTP)
+			if err := c.run(p); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := json.MarshalIndent(handler.requests, "", "\t")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+
+			path := filepath.Join("testdata", "contract", c.name+".json")
+			if *updateGolden {
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Fatalf("requests differ from %s:\n%s", path, strings.TrimSpace(string(got)))
+			}
+		})
+	}
+}
+
+func appendRecord(r libdns.Record) func(p *hetzner.Provider) error {
+	return func(p *hetzner.Provider) error {
+		_, err := p.AppendRecords(context.TODO(), "example.com", []libdns.Record{r})
+		return err
+	}
+}
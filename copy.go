@@ -0,0 +1,52 @@
+package hetzner
+
+import (
+	"context"
+	"strings"
+
+	"github.com/libdns/libdns"
+)
+
+// CopyRecords recreates the records of srcZone for which filter returns true,
+// or all records if filter is nil, in dstZone, and returns the created
+// records.
+//
+// Names are rewritten to stay the same fully qualified names if dstZone is
+// below srcZone, e.g. "www.sub" in example.com becomes "www" in
+// sub.example.com when splitting a subdomain into its own zone; records
+// outside of dstZone are skipped then. Otherwise relative names are kept,
+// e.g. to clone example.com into example.net. SOA records and the apex NS
+// records of the destination are never copied.
+func (p *Provider) CopyRecords(ctx context.Context, srcZone string, dstZone string, filter func(libdns.Record) bool) ([]libdns.Record, error) {
+	srcZone, dstZone = unFQDN(srcZone), unFQDN(dstZone)
+
+	records, err := p.GetRecords(ctx, srcZone)
+	if err != nil {
+		return nil, err
+	}
+
+	dst := strings.ToLower(dstZone)
+	subzone := strings.HasSuffix(dst, "."+strings.ToLower(srcZone))
+	var copies []libdns.Record
+	for _, r := range records {
+		if r.Type == "SOA" || (filter != nil && !filter(r)) {
+			continue
+		}
+
+		if subzone {
+			fqdn := strings.ToLower(unFQDN(AbsoluteName(r.Name, srcZone)))
+			if fqdn != dst && !strings.HasSuffix(fqdn, "."+dst) {
+				continue
+			}
+			r.Name = normalizeRecordName(fqdn, dstZone)
+		}
+		if isApexNSOrSOA(r, dstZone) {
+			continue
+		}
+
+		r.ID = ""
+		copies = append(copies, r)
+	}
+
+	return p.AppendRecords(ctx, dstZone, copies)
+}
@@ -0,0 +1,40 @@
+package hetzner_test
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/libdns/libdns"
+)
+
+func Test_CopyRecords(t *testing.T) {
+	p, api := newFakeProvider(t,
+		"NS @ hydrogen.ns.hetzner.com.",
+		"A www 192.0.2.1",
+		"A www.sub 192.0.2.2",
+		"TXT sub v=spf1 -all",
+		"TXT _acme-challenge.sub token",
+	)
+	subID := api.addZone("sub.example.com", "NS @ hydrogen.ns.hetzner.com.")
+	otherID := api.addZone("example.net")
+
+	notACME := func(r libdns.Record) bool { return !strings.HasPrefix(r.Name, "_acme-challenge") }
+	if _, err := p.CopyRecords(context.TODO(), "example.com", "sub.example.com.", notACME); err != nil {
+		t.Fatal(err)
+	}
+	records := api.dump(subID)
+	slices.Sort(records)
+	if !slices.Equal(records, []string{"A www 192.0.2.2", "NS @ hydrogen.ns.hetzner.com.", "TXT @ v=spf1 -all"}) {
+		t.Fatalf("unexpected records in sub.example.com => %v", records)
+	}
+
+	if _, err := p.CopyRecords(context.TODO(), "example.com", "example.net", nil); err != nil {
+		t.Fatal(err)
+	}
+	records = api.dump(otherID)
+	if len(records) != 4 || slices.Contains(records, "NS @ hydrogen.ns.hetzner.com.") {
+		t.Fatalf("unexpected records in example.net => %v", records)
+	}
+}
@@ -0,0 +1,150 @@
+package hetzner_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/libdns/hetzner"
+	"github.com/libdns/libdns"
+)
+
+func Test_Dangerous(t *testing.T) {
+	zone := []string{"SOA @ ns1.example.net. hostmaster.example.com. 1 86400 10800 3600000 3600", "NS @ ns1.example.net.", "A www 192.0.2.1", "NS sub ns1.example.org."}
+
+	tests := []struct {
+		name           string
+		allowDangerous bool
+		op             func(p *hetzner.Provider) error
+		dangerous      bool
+		want           []string
+	}{
+		{
+			name: "create apex NS with SetRecords",
+			op: func(p *hetzner.Provider) error {
+				_, err := p.SetRecords(context.TODO(), "example.com", []libdns.Record{{Type: "NS", Name: "@", Value: "ns1.example.net."}, {Type: "NS", Name: "@", Value: "ns2.example.net."}})
+				return err
+			},
+			want: append(slices.Clone(zone), "NS @ ns2.example.net."),
+		},
+		{
+			name: "create apex NS with AppendRecords",
+			op: func(p *hetzner.Provider) error {
+				_, err := p.AppendRecords(context.TODO(), "example.com", []libdns.Record{{Type: "NS", Name: "@", Value: "ns2.example.net."}})
+				return err
+			},
+			want: append(slices.Clone(zone), "NS @ ns2.example.net."),
+		},
+		{
+			name: "replace apex NS with SetRecords",
+			op: func(p *hetzner.Provider) error {
+				_, err := p.SetRecords(context.TODO(), "example.com", []libdns.Record{{Type: "NS", Name: "@", Value: "ns2.example.net."}})
+				return err
+			},
+			dangerous: true,
+		},
+		{
+			name: "replace apex NS by ID",
+			op: func(p *hetzner.Provider) error {
+				_, err := p.SetRecords(context.TODO(), "example.com", []libdns.Record{{ID: "r2", Type: "NS", Name: "@", Value: "ns2.example.net."}})
+				return err
+			},
+			dangerous: true,
+		},
+		{
+			name:           "replace apex NS with AllowDangerous",
+			allowDangerous: true,
+			op: func(p *hetzner.Provider) error {
+				_, err := p.SetRecords(context.TODO(), "example.com", []libdns.Record{{Type: "NS", Name: "@", Value: "ns2.example.net."}})
+				return err
+			},
+			want: []string{zone[0], "NS @ ns2.example.net.", zone[2], zone[3]},
+		},
+		{
+			name: "replace delegation NS",
+			op: func(p *hetzner.Provider) error {
+				_, err := p.SetRecords(context.TODO(), "example.com", []libdns.Record{{Type: "NS", Name: "sub", Value: "ns2.example.org."}})
+				return err
+			},
+			want: []string{zone[0], zone[1], zone[2], "NS sub ns2.example.org."},
+		},
+		{
+			name: "replace apex NS with CompareAndSwapRecord",
+			op: func(p *hetzner.Provider) error {
+				_, err := p.CompareAndSwapRecord(context.TODO(), "example.com", "ns1.example.net.", libdns.Record{Type: "NS", Name: "@", Value: "ns2.example.net."})
+				return err
+			},
+			dangerous: true,
+		},
+		{
+			name: "delete apex NS",
+			op: func(p *hetzner.Provider) error {
+				_, err := p.DeleteRecords(context.TODO(), "example.com", []libdns.Record{{Type: "NS", Name: "@", Value: "ns1.example.net."}})
+				return err
+			},
+			dangerous: true,
+		},
+		{
+			name: "delete apex NS by ID",
+			op: func(p *hetzner.Provider) error {
+				_, err := p.DeleteRecords(context.TODO(), "example.com", []libdns.Record{{ID: "r2"}})
+				return err
+			},
+			dangerous: true,
+		},
+		{
+			name: "replace apex NS by ID with another type",
+			op: func(p *hetzner.Provider) error {
+				_, err := p.SetRecords(context.TODO(), "example.com", []libdns.Record{{ID: "r2", Type: "A", Name: "www", Value: "192.0.2.2"}})
+				return err
+			},
+			dangerous: true,
+		},
+		{
+			name: "delete apex SOA by ID with another type",
+			op: func(p *hetzner.Provider) error {
+				_, err := p.DeleteRecords(context.TODO(), "example.com", []libdns.Record{{ID: "r1", Type: "A", Name: "www", Value: "192.0.2.1"}})
+				return err
+			},
+			dangerous: true,
+		},
+		{
+			name: "delete apex NS with DeleteRRset",
+			op: func(p *hetzner.Provider) error {
+				_, err := p.DeleteRRset(context.TODO(), "example.com", "@", "NS")
+				return err
+			},
+			dangerous: true,
+		},
+		{
+			name: "delete all records",
+			op: func(p *hetzner.Provider) error {
+				_, err := p.DeleteAllRecords(context.TODO(), "example.com", hetzner.DeleteAllOptions{Confirm: true})
+				return err
+			},
+			want: zone[:2],
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p, api := newFakeProvider(t, zone...)
+			p.AllowDangerous = test.allowDangerous
+
+			err := test.op(p)
+			if errors.Is(err, hetzner.ErrDangerousOperation) != test.dangerous {
+				t.Fatalf("unexpected error => %v", err)
+			}
+			want := test.want
+			if test.dangerous {
+				want = zone
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if records := api.dump("z"); !slices.Equal(records, want) {
+				t.Fatalf("%v != %v", records, want)
+			}
+		})
+	}
+}
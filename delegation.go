@@ -0,0 +1,110 @@
+package hetzner
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// Delegation describes the delegation of a child zone to other nameservers.
+type Delegation struct {
+	// Child is the name of the child zone, relative to the parent zone
+	// (e.g. "sub") or fully qualified (e.g. "sub.example.com.").
+	Child string
+
+	// Nameservers are the host names of the child zone's nameservers.
+	Nameservers []string
+
+	// Glue maps nameservers inside the child zone to their IP addresses.
+	// Glue records are required for such nameservers, as they can't be
+	// resolved otherwise.
+	Glue map[string][]string
+
+	// TTL is the TTL of the created records. If zero, the provider's
+	// defaults apply.
+	TTL time.Duration
+}
+
+// Records returns the NS and glue A/AAAA records delegating d.Child in zone.
+// It returns an error wrapping ErrInvalidRecord if the child is not inside
+// the zone, a nameserver is not a valid host name, or glue is given for a
+// nameserver outside the child zone.
+func (d Delegation) Records(zone string) ([]libdns.Record, error) {
+	zone = strings.ToLower(unFQDN(zone))
+
+	child, err := delegatedName(d.Child, zone)
+	if err != nil {
+		return nil, err
+	}
+	if len(d.Nameservers) == 0 {
+		return nil, fmt.Errorf("%w: delegation of %q has no nameservers", ErrInvalidRecord, child)
+	}
+
+	var records []libdns.Record
+	for _, ns := range d.Nameservers {
+		if err := validateHostname(ns); err != nil {
+			return nil, fmt.Errorf("%w: nameserver of %q: %v", ErrInvalidRecord, child, err)
+		}
+		records = append(records, libdns.Record{Type: "NS", Name: child, Value: ns, TTL: d.TTL})
+	}
+
+	childFQDN := child + "." + zone
+	for _, ns := range slices.Sorted(maps.Keys(d.Glue)) {
+		ips := d.Glue[ns]
+		host := strings.ToLower(unFQDN(ns))
+		if host != childFQDN && !strings.HasSuffix(host, "."+childFQDN) {
+			return nil, fmt.Errorf("%w: glue for nameserver %q outside of the child zone %s", ErrInvalidRecord, ns, childFQDN)
+		}
+		for _, ip := range ips {
+			addr, err := netip.ParseAddr(ip)
+			if err != nil {
+				return nil, fmt.Errorf("%w: glue for nameserver %q: %q is not an IP address", ErrInvalidRecord, ns, ip)
+			}
+			recordType := "A"
+			if addr.Is6() && !addr.Is4In6() {
+				recordType = "AAAA"
+			}
+			records = append(records, libdns.Record{Type: recordType, Name: normalizeRecordName(host, zone), Value: ip, TTL: d.TTL})
+		}
+	}
+
+	return records, nil
+}
+
+// delegatedName returns the name of the child zone relative to zone, or an
+// error if it is not strictly below the zone.
+func delegatedName(child string, zone string) (string, error) {
+	name := strings.ToLower(child)
+	if strings.HasSuffix(name, ".") {
+		name = unFQDN(name)
+		if name != zone && !strings.HasSuffix(name, "."+zone) {
+			return "", fmt.Errorf("%w: child zone %q is not inside zone %s", ErrInvalidRecord, child, zone)
+		}
+	}
+
+	name = normalizeRecordName(name, zone)
+	if name == "@" || IsWildcard(name) {
+		return "", fmt.Errorf("%w: %q can't be delegated", ErrInvalidRecord, child)
+	}
+	if err := validateHostname(name); err != nil {
+		return "", fmt.Errorf("%w: child zone: %v", ErrInvalidRecord, err)
+	}
+	return name, nil
+}
+
+// Delegate creates the NS and glue records delegating a child zone to other
+// nameservers, see Delegation.Records. Records that already exist are kept.
+func (p *Provider) Delegate(ctx context.Context, zone string, d Delegation) ([]libdns.Record, error) {
+	records, err := d.Records(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.AppendRecords(ctx, zone, records)
+}
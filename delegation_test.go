@@ -0,0 +1,47 @@
+package hetzner_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/libdns/hetzner"
+)
+
+func Test_DelegationRecords(t *testing.T) {
+	d := hetzner.Delegation{
+		Child:       "sub.example.com.",
+		Nameservers: []string{"ns1.sub.example.com.", "ns.other.net."},
+		Glue:        map[string][]string{"ns1.sub.example.com.": {"192.0.2.1", "2001:db8::1"}},
+	}
+
+	records, err := d.Records("example.com.")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"NS sub ns1.sub.example.com.",
+		"NS sub ns.other.net.",
+		"A ns1.sub 192.0.2.1",
+		"AAAA ns1.sub 2001:db8::1",
+	}
+	if len(records) != len(expected) {
+		t.Fatalf("len(records) != %d => %d", len(expected), len(records))
+	}
+	for i, record := range records {
+		if got := record.Type + " " + record.Name + " " + record.Value; got != expected[i] {
+			t.Fatalf("%q != %q", got, expected[i])
+		}
+	}
+
+	for _, invalid := range []hetzner.Delegation{
+		{Child: "sub.other.com.", Nameservers: []string{"ns.other.net."}},
+		{Child: "@", Nameservers: []string{"ns.other.net."}},
+		{Child: "sub"},
+		{Child: "sub", Nameservers: []string{"ns.other.net."}, Glue: map[string][]string{"ns.other.net.": {"192.0.2.1"}}},
+	} {
+		if _, err := invalid.Records("example.com"); !errors.Is(err, hetzner.ErrInvalidRecord) {
+			t.Fatalf("%+v: err != ErrInvalidRecord => %v", invalid, err)
+		}
+	}
+}
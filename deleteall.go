@@ -0,0 +1,84 @@
+package hetzner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/libdns/libdns"
+)
+
+// DeleteAllOptions configures DeleteAllRecords.
+type DeleteAllOptions struct {
+	// Confirm must be set to true, otherwise DeleteAllRecords refuses to
+	// delete anything and returns ErrNotConfirmed.
+	Confirm bool
+
+	// Concurrency is the maximum number of delete requests in flight.
+	// Defaults to Provider.Concurrency.
+	Concurrency int
+}
+
+// DeleteResult is the outcome of deleting a single record.
+type DeleteResult struct {
+	Record libdns.Record
+	Err    error
+}
+
+// DeleteAllRecords deletes all records in the zone except the NS and SOA
+// records at the zone apex. It returns one result per record it attempted to
+// delete, in the order the records were listed. If any deletion failed, the
+// returned error summarizes how many; the individual errors are in the
+// results.
+func (p *Provider) DeleteAllRecords(ctx context.Context, zone string, opts DeleteAllOptions) ([]DeleteResult, error) {
+	if !opts.Confirm {
+		return nil, ErrNotConfirmed
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = p.concurrency()
+	}
+
+	zone = unFQDN(zone)
+	records, err := p.getAllRecords(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, err = p.withRegistry(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	// registry records are removed along with the records they own
+	var results []DeleteResult
+	for _, record := range p.withoutRegistryRecords(zone, records) {
+		if isApexNSOrSOA(record, zone) {
+			continue
+		}
+		results = append(results, DeleteResult{Record: record})
+	}
+
+	forEachConcurrently(len(results), concurrency, func(i int) {
+		if ctx.Err() != nil {
+			results[i].Err = ctx.Err()
+			return
+		}
+		results[i].Err = p.deleteRecord(ctx, zone, results[i].Record)
+	})
+
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	if failed > 0 && ctx.Err() != nil {
+		return results, fmt.Errorf("failed to delete %d of %d records: %w", failed, len(results), ctx.Err())
+	}
+	if failed > 0 {
+		return results, fmt.Errorf("failed to delete %d of %d records", failed, len(results))
+	}
+
+	return results, nil
+}
@@ -0,0 +1,144 @@
+package hetzner
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/libdns/libdns"
+)
+
+// ZoneDiff is the difference between two sets of records, as computed by
+// Diff.
+type ZoneDiff struct {
+	// Added are the records only in the second set.
+	Added []libdns.Record
+
+	// Removed are the records only in the first set.
+	Removed []libdns.Record
+
+	// Changed are records in both sets whose value or TTL differ.
+	Changed []RecordChange
+}
+
+// RecordChange is a record that changed between two sets of records.
+type RecordChange struct {
+	Old libdns.Record
+	New libdns.Record
+}
+
+// Empty reports whether the sets of records were equal.
+func (d ZoneDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Diff compares the records a and b, both relative to the same zone, the
+// way Hetzner treats them: names are compared case-insensitively with
+// trailing dots and "@" normalized, as are the host names in CNAME, NS,
+// PTR, MX and SRV values, A and AAAA addresses are compared in canonical
+// form, and records without a TTL match records with any TTL. Record IDs are
+// ignored.
+//
+// Records are matched by name, type and value; a record whose TTL differs is
+// changed. Within an RRset, records of a without a match in b are paired up
+// with those of b without a match in a as changes, and the remainder is
+// removed or added.
+func Diff(a []libdns.Record, b []libdns.Record) ZoneDiff {
+	setsA, keys := diffSets(a, nil)
+	setsB, keys := diffSets(b, keys)
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].name != keys[j].name {
+			return keys[i].name < keys[j].name
+		}
+		return keys[i].recordType < keys[j].recordType
+	})
+
+	var diff ZoneDiff
+	for _, key := range keys {
+		unmatched := append([]libdns.Record(nil), setsA[key]...)
+		var added []libdns.Record
+
+		for _, r := range setsB[key] {
+			found := -1
+			for i, old := range unmatched {
+				if diffValue(old) == diffValue(r) {
+					found = i
+					break
+				}
+			}
+			if found < 0 {
+				added = append(added, r)
+				continue
+			}
+			old := unmatched[found]
+			unmatched = append(unmatched[:found], unmatched[found+1:]...)
+			if old.TTL != 0 && r.TTL != 0 && old.TTL != r.TTL {
+				diff.Changed = append(diff.Changed, RecordChange{Old: old, New: r})
+			}
+		}
+
+		for len(unmatched) > 0 && len(added) > 0 {
+			diff.Changed = append(diff.Changed, RecordChange{Old: unmatched[0], New: added[0]})
+			unmatched, added = unmatched[1:], added[1:]
+		}
+		diff.Removed = append(diff.Removed, unmatched...)
+		diff.Added = append(diff.Added, added...)
+	}
+
+	return diff
+}
+
+// diffSets groups records by normalized name and type, and appends the keys
+// not seen before to keys.
+func diffSets(records []libdns.Record, keys []rrsetKey) (map[rrsetKey][]libdns.Record, []rrsetKey) {
+	seen := map[rrsetKey]bool{}
+	for _, key := range keys {
+		seen[key] = true
+	}
+
+	sets := map[rrsetKey][]libdns.Record{}
+	for _, r := range records {
+		key := rrsetKey{name: normalizeRecordName(r.Name, ""), recordType: strings.ToUpper(r.Type)}
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+		sets[key] = append(sets[key], r)
+	}
+	return sets, keys
+}
+
+// diffValue returns the value of r as compared by Diff.
+func diffValue(r libdns.Record) string {
+	switch strings.ToUpper(r.Type) {
+	case "CNAME", "NS", "PTR", "MX", "SRV":
+		return strings.TrimSuffix(strings.ToLower(r.Value), ".")
+	default:
+		return canonicalValue(strings.ToUpper(r.Type), r.Value)
+	}
+}
+
+// CompareZones diffs the records of zoneA against those of zoneB, see Diff,
+// e.g. to verify a migration. Names are compared relative to their zone and
+// the SOA records are ignored.
+func (p *Provider) CompareZones(ctx context.Context, zoneA string, zoneB string) (ZoneDiff, error) {
+	a, err := p.GetRecords(ctx, zoneA)
+	if err != nil {
+		return ZoneDiff{}, err
+	}
+	b, err := p.GetRecords(ctx, zoneB)
+	if err != nil {
+		return ZoneDiff{}, err
+	}
+
+	withoutSOA := func(records []libdns.Record) []libdns.Record {
+		filtered := records[:0]
+		for _, r := range records {
+			if r.Type != "SOA" {
+				filtered = append(filtered, r)
+			}
+		}
+		return filtered
+	}
+	return Diff(withoutSOA(a), withoutSOA(b)), nil
+}
@@ -0,0 +1,41 @@
+package hetzner_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/libdns/hetzner"
+	"github.com/libdns/libdns"
+)
+
+func Test_Diff(t *testing.T) {
+	a := []libdns.Record{
+		{Type: "A", Name: "www", Value: "192.0.2.1", TTL: time.Hour},
+		{Type: "CNAME", Name: "docs", Value: "Pages.Example.NET."},
+		{Type: "TXT", Name: "@", Value: "v=spf1 -all"},
+		{Type: "MX", Name: "", Value: "10 mail.example.com.", TTL: time.Hour},
+		{Type: "A", Name: "old", Value: "192.0.2.9"},
+	}
+	b := []libdns.Record{
+		{ID: "1", Type: "A", Name: "WWW", Value: "192.0.2.1", TTL: time.Hour},
+		{Type: "CNAME", Name: "docs.", Value: "pages.example.net"},
+		{Type: "TXT", Name: "", Value: "v=spf1 include:example.net -all"},
+		{Type: "MX", Name: "@", Value: "10 mail.example.com.", TTL: 5 * time.Minute},
+		{Type: "A", Name: "new", Value: "192.0.2.10"},
+	}
+
+	diff := hetzner.Diff(a, b)
+	if len(diff.Added) != 1 || diff.Added[0].Name != "new" {
+		t.Fatalf("unexpected added records => %+v", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].Name != "old" {
+		t.Fatalf("unexpected removed records => %+v", diff.Removed)
+	}
+	if len(diff.Changed) != 2 || diff.Changed[0].New.Type != "MX" || diff.Changed[1].New.Type != "TXT" {
+		t.Fatalf("unexpected changed records => %+v", diff.Changed)
+	}
+
+	if !hetzner.Diff(a, a).Empty() {
+		t.Fatal("diff of equal records is not empty")
+	}
+}
@@ -0,0 +1,181 @@
+package hetzner
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// TLSA is the value of a TLSA record for DANE (RFC 6698), e.g.
+// 3 1 1 <hex data>.
+type TLSA struct {
+	Usage        uint8
+	Selector     uint8
+	MatchingType uint8
+
+	// Data is the certificate association data, hex encoded.
+	Data string
+}
+
+// String formats t as a TLSA record value.
+func (t TLSA) String() string {
+	return fmt.Sprintf("%d %d %d %s", t.Usage, t.Selector, t.MatchingType, strings.ToLower(t.Data))
+}
+
+// Record returns a TLSA record with t as its value. name is usually of the
+// form "_<port>._<protocol>.<host>", e.g. "_443._tcp.www".
+func (t TLSA) Record(name string, ttl time.Duration) libdns.Record {
+	return libdns.Record{Type: "TLSA", Name: name, Value: t.String(), TTL: ttl}
+}
+
+// ParseTLSA parses and validates a TLSA record value.
+func ParseTLSA(value string) (TLSA, error) {
+	numbers, data, err := splitRdata(value, 3)
+	if err != nil {
+		return TLSA{}, fmt.Errorf("%q is not of the form \"<usage> <selector> <matching type> <data>\"", value)
+	}
+
+	t := TLSA{Data: data}
+	if t.Usage, err = parseUint8("TLSA usage", numbers[0], 3); err != nil {
+		return TLSA{}, err
+	}
+	if t.Selector, err = parseUint8("TLSA selector", numbers[1], 1); err != nil {
+		return TLSA{}, err
+	}
+	if t.MatchingType, err = parseUint8("TLSA matching type", numbers[2], 2); err != nil {
+		return TLSA{}, err
+	}
+
+	// matching types 1 and 2 are SHA-256 and SHA-512 hashes
+	lengths := map[uint8]int{1: 32, 2: 64}
+	if err := validateHexData("TLSA data", data, lengths[t.MatchingType]); err != nil {
+		return TLSA{}, err
+	}
+	return t, nil
+}
+
+// DS is the value of a DS record delegating a DNSSEC-signed child zone
+// (RFC 4034), e.g. 12345 13 2 <hex digest>.
+type DS struct {
+	KeyTag     uint16
+	Algorithm  uint8
+	DigestType uint8
+
+	// Digest is the digest of the child zone's DNSKEY, hex encoded.
+	Digest string
+}
+
+// String formats d as a DS record value.
+func (d DS) String() string {
+	return fmt.Sprintf("%d %d %d %s", d.KeyTag, d.Algorithm, d.DigestType, strings.ToLower(d.Digest))
+}
+
+// Record returns a DS record with d as its value. name is the name of the
+// delegated child zone relative to the parent zone.
+func (d DS) Record(name string, ttl time.Duration) libdns.Record {
+	return libdns.Record{Type: "DS", Name: name, Value: d.String(), TTL: ttl}
+}
+
+// ParseDS parses and validates a DS record value.
+func ParseDS(value string) (DS, error) {
+	numbers, digest, err := splitRdata(value, 3)
+	if err != nil {
+		return DS{}, fmt.Errorf("%q is not of the form \"<key tag> <algorithm> <digest type> <digest>\"", value)
+	}
+
+	keyTag, err := strconv.ParseUint(numbers[0], 10, 16)
+	if err != nil {
+		return DS{}, fmt.Errorf("DS key tag %q is not a number between 0 and 65535", numbers[0])
+	}
+	d := DS{KeyTag: uint16(keyTag), Digest: digest}
+	if d.Algorithm, err = parseUint8("DS algorithm", numbers[1], 255); err != nil {
+		return DS{}, err
+	}
+	if d.DigestType, err = parseUint8("DS digest type", numbers[2], 255); err != nil {
+		return DS{}, err
+	}
+
+	// digest types 1, 2 and 4 are SHA-1, SHA-256 and SHA-384
+	lengths := map[uint8]int{1: 20, 2: 32, 4: 48}
+	if err := validateHexData("DS digest", digest, lengths[d.DigestType]); err != nil {
+		return DS{}, err
+	}
+	return d, nil
+}
+
+// SSHFP is the value of an SSHFP record (RFC 4255), e.g. 4 2 <hex
+// fingerprint>.
+//
+// Hetzner DNS does not support SSHFP records at the time of writing; SSHFP
+// is provided for formatting and validating values, e.g. for other providers
+// or zone files.
+type SSHFP struct {
+	Algorithm uint8
+	Type      uint8
+
+	// Fingerprint is the host key fingerprint, hex encoded.
+	Fingerprint string
+}
+
+// String formats s as an SSHFP record value.
+func (s SSHFP) String() string {
+	return fmt.Sprintf("%d %d %s", s.Algorithm, s.Type, strings.ToLower(s.Fingerprint))
+}
+
+// ParseSSHFP parses and validates an SSHFP record value.
+func ParseSSHFP(value string) (SSHFP, error) {
+	numbers, fingerprint, err := splitRdata(value, 2)
+	if err != nil {
+		return SSHFP{}, fmt.Errorf("%q is not of the form \"<algorithm> <type> <fingerprint>\"", value)
+	}
+
+	s := SSHFP{Fingerprint: fingerprint}
+	if s.Algorithm, err = parseUint8("SSHFP algorithm", numbers[0], 255); err != nil {
+		return SSHFP{}, err
+	}
+	if s.Type, err = parseUint8("SSHFP type", numbers[1], 255); err != nil {
+		return SSHFP{}, err
+	}
+
+	// types 1 and 2 are SHA-1 and SHA-256
+	lengths := map[uint8]int{1: 20, 2: 32}
+	if err := validateHexData("SSHFP fingerprint", fingerprint, lengths[s.Type]); err != nil {
+		return SSHFP{}, err
+	}
+	return s, nil
+}
+
+// splitRdata splits value into n numeric fields followed by hex data, which
+// may contain whitespace as in zone files.
+func splitRdata(value string, n int) ([]string, string, error) {
+	fields := strings.Fields(value)
+	if len(fields) <= n {
+		return nil, "", fmt.Errorf("too few fields")
+	}
+	return fields[:n], strings.Join(fields[n:], ""), nil
+}
+
+func parseUint8(field string, value string, max uint8) (uint8, error) {
+	n, err := strconv.ParseUint(value, 10, 8)
+	if err != nil || n > uint64(max) {
+		return 0, fmt.Errorf("%s %q is not a number between 0 and %d", field, value, max)
+	}
+	return uint8(n), nil
+}
+
+// validateHexData checks that data is hex encoded and, if length is not
+// zero, decodes to length bytes.
+func validateHexData(field string, data string, length int) error {
+	decoded, err := hex.DecodeString(data)
+	if err != nil {
+		return fmt.Errorf("%s is not hex encoded: %v", field, err)
+	}
+	if length != 0 && len(decoded) != length {
+		return fmt.Errorf("%s has %d bytes, expected %d", field, len(decoded), length)
+	}
+	return nil
+}
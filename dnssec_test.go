@@ -0,0 +1,58 @@
+package hetzner_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/libdns/hetzner"
+	"github.com/libdns/libdns"
+)
+
+func Test_DNSSECRecords(t *testing.T) {
+	sha256 := strings.Repeat("ab", 32)
+
+	tlsa, err := hetzner.ParseTLSA("3 1 1 " + sha256[:32] + " " + sha256[32:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tlsa.String() != "3 1 1 "+sha256 {
+		t.Fatalf("unexpected TLSA value => %s", tlsa)
+	}
+
+	ds, err := hetzner.ParseDS("12345 13 2 " + strings.ToUpper(sha256))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ds.KeyTag != 12345 || ds.String() != "12345 13 2 "+sha256 {
+		t.Fatalf("unexpected DS value => %s", ds)
+	}
+
+	if _, err := hetzner.ParseSSHFP("4 2 " + sha256); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, invalid := range []libdns.Record{
+		{Type: "TLSA", Value: "4 1 1 " + sha256},
+		{Type: "TLSA", Value: "3 1 1 abcd"},
+		{Type: "DS", Value: "12345 13 2 xyz"},
+		{Type: "SSHFP", Value: "4 2"},
+	} {
+		if err := hetzner.ValidateRecord(invalid); err == nil {
+			t.Fatalf("ValidateRecord(%q) succeeded", invalid.Value)
+		}
+	}
+
+	// the values are sent to the API unchanged
+	p := newTestProvider(t, batchHandler)
+	records := []libdns.Record{tlsa.Record("_443._tcp.www", 0), ds.Record("sub", 0)}
+	created, err := p.AppendRecords(context.TODO(), "example.com.", records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, record := range created {
+		if record.Type != records[i].Type || record.Value != records[i].Value {
+			t.Fatalf("%s %q != %s %q", record.Type, record.Value, records[i].Type, records[i].Value)
+		}
+	}
+}
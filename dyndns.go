@@ -0,0 +1,181 @@
+package hetzner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// IPDetector returns the current public IP address of the machine.
+type IPDetector func(ctx context.Context) (netip.Addr, error)
+
+// HTTPDetector returns an IPDetector that fetches url, which must respond
+// with the caller's IP address as plain text, like https://api.ipify.org for
+// IPv4 and https://api6.ipify.org for IPv6 do. If client is nil,
+// http.DefaultClient is used.
+func HTTPDetector(client *http.Client, url string) IPDetector {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return func(ctx context.Context) (netip.Addr, error) {
+		request, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return netip.Addr{}, err
+		}
+		response, err := client.Do(request)
+		if err != nil {
+			return netip.Addr{}, err
+		}
+		defer response.Body.Close()
+
+		if response.StatusCode != http.StatusOK {
+			return netip.Addr{}, fmt.Errorf("%s: %s", url, response.Status)
+		}
+		body, err := io.ReadAll(io.LimitReader(response.Body, 64))
+		if err != nil {
+			return netip.Addr{}, err
+		}
+		return netip.ParseAddr(strings.TrimSpace(string(body)))
+	}
+}
+
+// DynDNS keeps A and AAAA records pointed at the machine's current public
+// addresses, e.g. for a home server behind a connection with a changing IP.
+// The addresses are detected every Interval, and the records are only
+// written when they differ from the detected addresses.
+type DynDNS struct {
+	Provider *Provider
+	Zone     string
+
+	// Names are the names of the records to update.
+	Names []string
+
+	// IPv4 and IPv6 detect the current address of each family; the records
+	// of a family without detector are left alone.
+	IPv4 IPDetector
+	IPv6 IPDetector
+
+	// Interval is the time between two detections. Jitter, if positive,
+	// adds a random delay of up to Jitter to every interval, so many
+	// machines started at once don't poll in lockstep.
+	Interval time.Duration
+	Jitter   time.Duration
+
+	// TTL is the TTL of the records. If zero, the provider's defaults
+	// apply.
+	TTL time.Duration
+
+	// OnUpdate, if set, is called after the records of a family were
+	// pointed at a newly detected address, including the first one.
+	OnUpdate func(addr netip.Addr)
+
+	// OnError, if set, is called with the errors of failed detections and
+	// updates while Run keeps going.
+	OnError func(err error)
+
+	mu   sync.Mutex
+	last map[string]netip.Addr
+}
+
+// Run updates the records immediately and then every Interval, plus jitter,
+// until ctx is done, and returns the context's error.
+func (d *DynDNS) Run(ctx context.Context) error {
+	if d.Interval <= 0 {
+		return fmt.Errorf("%w: DynDNS.Interval must be positive", ErrInvalidConfig)
+	}
+
+	for {
+		if err := d.UpdateOnce(ctx); err != nil && d.OnError != nil {
+			d.OnError(err)
+		}
+
+		delay := d.Interval
+		if d.Jitter > 0 {
+			delay += time.Duration(rand.Int63n(int64(d.Jitter)))
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// UpdateOnce detects the current addresses and updates the records of every
+// family whose address changed since the last successful update. The first
+// update compares against the live records and only writes those that
+// differ.
+func (d *DynDNS) UpdateOnce(ctx context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.last == nil {
+		d.last = map[string]netip.Addr{}
+	}
+
+	var errs []error
+	for _, family := range []struct {
+		recordType string
+		detect     IPDetector
+		valid      func(netip.Addr) bool
+	}{
+		{"A", d.IPv4, netip.Addr.Is4},
+		{"AAAA", d.IPv6, netip.Addr.Is6},
+	} {
+		if family.detect == nil {
+			continue
+		}
+
+		addr, err := family.detect(ctx)
+		if err == nil && !family.valid(addr.Unmap()) {
+			err = fmt.Errorf("detected address %s is not suitable for %s records", addr, family.recordType)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("detecting %s address: %w", family.recordType, err))
+			continue
+		}
+		addr = addr.Unmap()
+		if d.last[family.recordType] == addr {
+			continue
+		}
+
+		if err := d.update(ctx, family.recordType, addr); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		d.last[family.recordType] = addr
+		if d.OnUpdate != nil {
+			d.OnUpdate(addr)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// update points the records of the given type of all names at addr.
+func (d *DynDNS) update(ctx context.Context, recordType string, addr netip.Addr) error {
+	zone := unFQDN(d.Zone)
+
+	var desired []libdns.Record
+	names := map[string]bool{}
+	for _, name := range d.Names {
+		desired = append(desired, libdns.Record{Type: recordType, Name: name, Value: addr.String(), TTL: d.TTL})
+		names[normalizeRecordName(name, zone)] = true
+	}
+
+	_, err := d.Provider.syncRRsets(ctx, zone, desired, func(r libdns.Record) bool {
+		return r.Type == recordType && names[normalizeRecordName(r.Name, zone)]
+	})
+	if err != nil {
+		return fmt.Errorf("updating %s records to %s: %w", recordType, addr, err)
+	}
+	return nil
+}
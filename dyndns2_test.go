@@ -0,0 +1,54 @@
+package hetzner_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/libdns/hetzner"
+)
+
+func Test_DynDNS2Handler(t *testing.T) {
+	p, api := newFakeProvider(t, "A home 192.0.2.1", "AAAA home 2001:db8::1")
+	h := &hetzner.DynDNS2Handler{Provider: p, Username: "router", Password: "secret", Hostnames: []string{"home.example.com"}}
+
+	testCases := []struct {
+		query    string
+		password string
+		remote   string
+		expected string
+	}{
+		{query: "hostname=home.example.com&myip=192.0.2.1", password: "wrong", expected: "badauth\n"},
+		{query: "hostname=home.example.com&myip=192.0.2.1", expected: "nochg 192.0.2.1\n"},
+		{query: "hostname=home.example.com&myip=192.0.2.2,2001:db8::1", expected: "good 192.0.2.2,2001:db8::1\n"},
+		{query: "hostname=home.example.com", remote: "198.51.100.1:4242", expected: "good 198.51.100.1\n"},
+		{query: "hostname=other.example.com,home.example.com&myip=198.51.100.1", expected: "nohost\nnochg 198.51.100.1\n"},
+		{query: "myip=192.0.2.1", expected: "notfqdn\n"},
+	}
+	for _, c := range testCases {
+		password := c.password
+		if password == "" {
+			password = "secret"
+		}
+		request := httptest.NewRequest("GET", "/nic/update?"+c.query, nil)
+		request.SetBasicAuth("router", password)
+		if c.remote != "" {
+			request.RemoteAddr = c.remote
+		}
+		response := httptest.NewRecorder()
+		h.ServeHTTP(response, request)
+
+		if body := response.Body.String(); body != c.expected {
+			t.Fatalf("%s: %q != %q", c.query, body, c.expected)
+		}
+		if c.password == "wrong" && response.Code != http.StatusUnauthorized {
+			t.Fatalf("%s: status %d != 401", c.query, response.Code)
+		}
+	}
+
+	records := api.dump("z")
+	if !slices.Equal(records, []string{"A home 198.51.100.1", "AAAA home 2001:db8::1"}) {
+		t.Fatalf("unexpected records => %v", records)
+	}
+}
@@ -0,0 +1,56 @@
+package hetzner_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"slices"
+	"testing"
+
+	"github.com/libdns/hetzner"
+)
+
+func Test_DynDNS(t *testing.T) {
+	p, api := newFakeProvider(t, "A @ 192.0.2.1", "A home 192.0.2.1", "AAAA home 2001:db8::1", "A other 192.0.2.1")
+
+	current := "192.0.2.1"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, current)
+	}))
+	t.Cleanup(server.Close)
+
+	var updates []netip.Addr
+	d := &hetzner.DynDNS{
+		Provider: p,
+		Zone:     "example.com",
+		Names:    []string{"@", "home"},
+		IPv4:     hetzner.HTTPDetector(nil, server.URL),
+		OnUpdate: func(addr netip.Addr) { updates = append(updates, addr) },
+	}
+
+	if err := d.UpdateOnce(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	current = "192.0.2.7"
+	if err := d.UpdateOnce(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.UpdateOnce(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+
+	records := api.dump("z")
+	if !slices.Equal(records, []string{"A @ 192.0.2.7", "A home 192.0.2.7", "AAAA home 2001:db8::1", "A other 192.0.2.1"}) {
+		t.Fatalf("unexpected records => %v", records)
+	}
+	if len(updates) != 2 || updates[1] != netip.MustParseAddr("192.0.2.7") {
+		t.Fatalf("unexpected updates => %v", updates)
+	}
+
+	current = "2001:db8::2"
+	if err := d.UpdateOnce(context.TODO()); err == nil {
+		t.Fatal("IPv6 address was used for A records")
+	}
+}
@@ -0,0 +1,245 @@
+package hetzner
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/libdns/libdns"
+)
+
+// ErrRecordNotFound is returned when no record in the zone matches a lookup.
+var ErrRecordNotFound = errors.New("record not found")
+
+// ErrZoneNotFound is returned when the account has no zone with the given
+// name.
+var ErrZoneNotFound = errors.New("zone not found")
+
+// ErrReverseZoneNotSupported is returned instead of ErrZoneNotFound for
+// in-addr.arpa and ip6.arpa zones that don't exist in Hetzner DNS. Reverse
+// DNS of Hetzner IP addresses is managed in the Hetzner Cloud Console or
+// Robot instead.
+var ErrReverseZoneNotSupported = errors.New("reverse zones are not supported by Hetzner DNS; set reverse DNS in the Hetzner Cloud Console or Robot")
+
+// zoneNotFound returns the error for a zone missing from the account.
+func zoneNotFound(zone string) error {
+	if IsReverseZone(zone) {
+		return fmt.Errorf("%w: %s", ErrReverseZoneNotSupported, zone)
+	}
+	return fmt.Errorf("%w: %s", ErrZoneNotFound, zone)
+}
+
+// ErrConflict is returned by CompareAndSwapRecord when the record's current
+// value doesn't match the expected value.
+var ErrConflict = errors.New("record was modified concurrently")
+
+// ErrUnauthorized is wrapped by the APIError of 401 Unauthorized responses:
+// the API token is invalid.
+var ErrUnauthorized = errors.New("unauthorized: the API token is invalid")
+
+// ErrForbidden is wrapped by the APIError of 403 Forbidden responses: the
+// API token is valid but may not access the resource, e.g. a zone of another
+// account.
+var ErrForbidden = errors.New("forbidden: the API token lacks permission")
+
+// APIError is returned when the Hetzner API responds with a non-2xx status.
+// For 401 and 403 responses it wraps ErrUnauthorized and ErrForbidden.
+type APIError struct {
+	StatusCode int
+
+	// Method and Path identify the failed request, e.g. "GET" and
+	// "/records/abc".
+	Method string
+	Path   string
+
+	// RequestID is the request or correlation ID the API returned with the
+	// response, if any. Reference it when contacting Hetzner support.
+	RequestID string
+
+	// SentRequestID is the ID the request was sent with in the X-Request-Id
+	// header, see WithRequestID.
+	SentRequestID string
+
+	// RateLimit is the rate limit reported with the response, if any.
+	RateLimit *RateLimit
+
+	// Message is the error message of the response body, if any.
+	Message string
+
+	// Hint suggests what to check for errors like 401 Unauthorized, if
+	// anything.
+	Hint string
+
+	// retryAfter is the delay requested by a Retry-After header, if any.
+	retryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s (%d)", http.StatusText(e.StatusCode), e.StatusCode)
+	if e.Method != "" {
+		msg = fmt.Sprintf("%s %s: %s", e.Method, e.Path, msg)
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.RequestID != "" {
+		msg += ", request ID " + e.RequestID
+	}
+	if e.SentRequestID != "" && e.SentRequestID != e.RequestID {
+		msg += ", sent request ID " + e.SentRequestID
+	}
+	if e.Hint != "" {
+		msg += " (" + e.Hint + ")"
+	}
+	return msg
+}
+
+func (e *APIError) Unwrap() error {
+	switch e.StatusCode {
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusForbidden:
+		return ErrForbidden
+	default:
+		return nil
+	}
+}
+
+// ValidationError is returned when the Hetzner API rejects a record with
+// 422 Unprocessable Entity. It wraps the APIError of the response.
+type ValidationError struct {
+	// Field is the record field the API complained about, "name", "type",
+	// "value" or "ttl", or empty if the message doesn't tell.
+	Field string
+
+	// Message is the API's explanation, e.g. "invalid TTL".
+	Message string
+
+	// Record is the record that was rejected, as sent to the API.
+	Record libdns.Record
+
+	Err *APIError
+}
+
+func (e *ValidationError) Error() string {
+	msg := "invalid record"
+	if e.Record.Type != "" {
+		msg = fmt.Sprintf("invalid %s record %q with value %q", e.Record.Type, e.Record.Name, e.Record.Value)
+	}
+	if e.Field != "" {
+		msg += ", field " + e.Field
+	}
+	return msg + ": " + e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// validationFields are the record fields, in the order they are looked for in
+// the messages of validation errors.
+var validationFields = []string{"ttl", "name", "type", "value"}
+
+// newValidationError returns the ValidationError for a 422 response.
+func newValidationError(apiErr *APIError) *ValidationError {
+	e := &ValidationError{Message: apiErr.Message, Err: apiErr}
+
+	// messages look like "422 Unprocessable Entity: invalid TTL"
+	words := strings.FieldsFunc(strings.ToLower(apiErr.Message), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '_'
+	})
+	for _, field := range validationFields {
+		if slices.Contains(words, field) {
+			e.Field = field
+			break
+		}
+	}
+	return e
+}
+
+// withRecord sets the record of a ValidationError err wraps, if any, and
+// returns err.
+func withRecord(err error, r libdns.Record) error {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		validationErr.Record = r
+	}
+	return err
+}
+
+// hasStatus reports whether err is an APIError with one of the given status
+// codes.
+func hasStatus(err error, codes ...int) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	for _, code := range codes {
+		if apiErr.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrDangerousOperation is returned when an operation would delete or replace
+// the zone's apex NS or SOA records and Provider.AllowDangerous is not set.
+var ErrDangerousOperation = errors.New("refusing to delete or replace apex NS/SOA record without AllowDangerous")
+
+// ErrNotConfirmed is returned by DeleteAllRecords when the caller didn't set
+// DeleteAllOptions.Confirm.
+var ErrNotConfirmed = errors.New("deleting all records requires confirmation")
+
+// ErrTooManyChanges is wrapped by TooManyChangesError.
+var ErrTooManyChanges = errors.New("too many changes")
+
+// TooManyChangesError is returned when an operation would change more
+// records than Provider.MaxChangesPerApply allows. Nothing was changed.
+type TooManyChangesError struct {
+	Limit int
+
+	// Plan lists the changes that were refused.
+	Plan Plan
+}
+
+func (e *TooManyChangesError) Error() string {
+	return fmt.Sprintf("refusing to apply %d changes (%d creates, %d updates, %d deletes), limit is %d",
+		e.Plan.Len(), len(e.Plan.Create), len(e.Plan.Update), len(e.Plan.Delete), e.Limit)
+}
+
+func (e *TooManyChangesError) Unwrap() error {
+	return ErrTooManyChanges
+}
+
+// ErrInvalidTTL is returned when a record's TTL can't be sent to Hetzner.
+var ErrInvalidTTL = errors.New("invalid TTL")
+
+// ErrCircuitOpen is returned without contacting the API while the circuit
+// breaker is open after repeated failures.
+var ErrCircuitOpen = errors.New("circuit breaker open: Hetzner API unavailable")
+
+// ErrInvalidConfig is wrapped by the errors returned from Provider.Validate.
+var ErrInvalidConfig = errors.New("invalid provider configuration")
+
+// RecordError is the error of a single record in a batch operation. Batch
+// operations return all of them joined with errors.Join.
+type RecordError struct {
+	Record libdns.Record
+	Err    error
+}
+
+func (e *RecordError) Error() string {
+	return fmt.Sprintf("%s record %q with value %q: %v", e.Record.Type, e.Record.Name, e.Record.Value, e.Err)
+}
+
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
+// ErrSkipped is reported for records a batch operation in BatchFailFast mode
+// didn't attempt because another record failed before.
+var ErrSkipped = errors.New("skipped after an earlier failure")
@@ -0,0 +1,161 @@
+package hetzner
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// FailoverTarget is a value a Failover points its record at, e.g. an IP
+// address for A records or a hostname for CNAME records.
+type FailoverTarget struct {
+	Value string
+
+	// Check reports whether the target is healthy, e.g. by connecting to
+	// it, by returning nil. A nil Check always reports healthy.
+	Check func(ctx context.Context) error
+}
+
+func (t FailoverTarget) healthy(ctx context.Context) error {
+	if t.Check == nil {
+		return nil
+	}
+	return t.Check(ctx)
+}
+
+// Failover points a record at a primary target while it is healthy and at a
+// backup target while it isn't, as health checked every Interval. To avoid
+// flapping, it only fails over after FailThreshold failed checks in a row,
+// only if the backup is healthy, and only fails back after RecoverThreshold
+// successful checks in a row.
+//
+// Resolvers keep using the old target for up to the record's TTL after a
+// switch, so the record is written with a short TTL.
+type Failover struct {
+	Provider *Provider
+	Zone     string
+	Name     string
+
+	// Type is the type of the record, "A", "AAAA" or "CNAME".
+	Type string
+
+	Primary FailoverTarget
+	Backup  FailoverTarget
+
+	// Interval is the time between two health checks.
+	Interval time.Duration
+
+	// FailThreshold and RecoverThreshold are the numbers of consecutive
+	// failed and successful checks of the primary target before failing
+	// over and back. Both default to 3.
+	FailThreshold    int
+	RecoverThreshold int
+
+	// TTL is the TTL of the record. It defaults to, and is raised to at
+	// least, MinTTL.
+	TTL time.Duration
+
+	// OnSwitch, if set, is called with the new target's value after the
+	// record was pointed at it.
+	OnSwitch func(value string)
+
+	// OnError, if set, is called with the errors of failed updates of the
+	// record while Run keeps going.
+	OnError func(err error)
+
+	mu         sync.Mutex
+	onBackup   bool
+	failures   int
+	successes  int
+	written    string
+	hasWritten bool
+}
+
+// Run checks the primary target immediately and then every Interval until
+// ctx is done, updating the record as needed, and returns the context's
+// error.
+func (f *Failover) Run(ctx context.Context) error {
+	if f.Interval <= 0 {
+		return fmt.Errorf("%w: Failover.Interval must be positive", ErrInvalidConfig)
+	}
+
+	ticker := time.NewTicker(f.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := f.CheckOnce(ctx); err != nil && f.OnError != nil {
+			f.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckOnce checks the health of the targets once and points the record at
+// the target that should be active. The record is written on the first
+// call and after every switch; a failed write is retried on the next call.
+func (f *Failover) CheckOnce(ctx context.Context) error {
+	primaryErr := f.Primary.healthy(ctx)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if primaryErr != nil {
+		f.failures++
+		f.successes = 0
+	} else {
+		f.successes++
+		f.failures = 0
+	}
+
+	switch {
+	case !f.onBackup && f.failures >= thresholdOrDefault(f.FailThreshold):
+		if err := f.Backup.healthy(ctx); err == nil {
+			f.onBackup = true
+		}
+	case f.onBackup && f.successes >= thresholdOrDefault(f.RecoverThreshold):
+		f.onBackup = false
+	}
+
+	target := f.Primary
+	if f.onBackup {
+		target = f.Backup
+	}
+	if f.hasWritten && f.written == target.Value {
+		return nil
+	}
+
+	ttl := f.TTL
+	if ttl < MinTTL {
+		ttl = MinTTL
+	}
+	zone := unFQDN(f.Zone)
+	record := libdns.Record{Type: f.Type, Name: f.Name, Value: target.Value, TTL: ttl}
+	_, err := f.Provider.syncRRsets(ctx, zone, []libdns.Record{record}, func(r libdns.Record) bool {
+		return r.Type == f.Type && sameName(r.Name, f.Name, zone)
+	})
+	if err != nil {
+		return fmt.Errorf("pointing %s record %q at %q: %w", f.Type, f.Name, target.Value, err)
+	}
+
+	switched := f.hasWritten
+	f.written, f.hasWritten = target.Value, true
+	if switched && f.OnSwitch != nil {
+		f.OnSwitch(target.Value)
+	}
+	return nil
+}
+
+func thresholdOrDefault(n int) int {
+	if n > 0 {
+		return n
+	}
+	return 3
+}
@@ -0,0 +1,56 @@
+package hetzner_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/libdns/hetzner"
+)
+
+func Test_Failover(t *testing.T) {
+	p, api := newFakeProvider(t, "A www 192.0.2.1")
+
+	primaryDown := false
+	var switches []string
+	f := &hetzner.Failover{
+		Provider: p,
+		Zone:     "example.com",
+		Name:     "www",
+		Type:     "A",
+		Primary: hetzner.FailoverTarget{Value: "192.0.2.1", Check: func(context.Context) error {
+			if primaryDown {
+				return errors.New("down")
+			}
+			return nil
+		}},
+		Backup:           hetzner.FailoverTarget{Value: "192.0.2.2"},
+		FailThreshold:    2,
+		RecoverThreshold: 3,
+		OnSwitch:         func(value string) { switches = append(switches, value) },
+	}
+
+	expect := func(value string) {
+		t.Helper()
+		if err := f.CheckOnce(context.TODO()); err != nil {
+			t.Fatal(err)
+		}
+		if records := api.dump("z"); !slices.Equal(records, []string{"A www " + value}) {
+			t.Fatalf("unexpected records => %v", records)
+		}
+	}
+
+	expect("192.0.2.1")
+	primaryDown = true
+	expect("192.0.2.1")
+	expect("192.0.2.2")
+	primaryDown = false
+	expect("192.0.2.2")
+	expect("192.0.2.2")
+	expect("192.0.2.1")
+
+	if !slices.Equal(switches, []string{"192.0.2.2", "192.0.2.1"}) {
+		t.Fatalf("unexpected switches => %v", switches)
+	}
+}
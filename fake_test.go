@@ -0,0 +1,174 @@
+package hetzner_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/libdns/hetzner"
+)
+
+// fakeRecord is a record as stored by fakeAPI.
+type fakeRecord struct {
+	ID     string `json:"id"`
+	ZoneID string `json:"zone_id"`
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	TTL    *int   `json:"ttl,omitempty"`
+}
+
+type fakeZone struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	TTL          int    `json:"ttl"`
+	RecordsCount int    `json:"records_count"`
+}
+
+// fakeAPI is an in-memory implementation of the parts of the Hetzner DNS
+// API the provider uses. It serves the zone "example.com" with ID "z" and
+// any zones added with addZone.
+type fakeAPI struct {
+	mu      sync.Mutex
+	zones   []fakeZone
+	records []fakeRecord
+	nextID  int
+}
+
+// newFakeProvider returns a provider talking to a new fakeAPI holding the
+// given records of example.com, each of the form "<type> <name> <value>".
+func newFakeProvider(t testing.TB, records ...string) (*hetzner.Provider, *fakeAPI) {
+	t.Helper()
+
+	api := &fakeAPI{zones: []fakeZone{{ID: "z", Name: "example.com", TTL: 86400}}}
+	for _, r := range records {
+		api.addRecord("z", r)
+	}
+	return newTestProvider(t, api.ServeHTTP), api
+}
+
+// addZone adds a zone with the given records and returns its ID.
+func (api *fakeAPI) addZone(name string, records ...string) string {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	id := fmt.Sprintf("z%d", len(api.zones)+1)
+	api.zones = append(api.zones, fakeZone{ID: id, Name: name, TTL: 86400})
+	for _, r := range records {
+		api.addRecord(id, r)
+	}
+	return id
+}
+
+func (api *fakeAPI) addRecord(zoneID string, r string) {
+	fields := strings.SplitN(r, " ", 3)
+	api.add(fakeRecord{ZoneID: zoneID, Type: fields[0], Name: fields[1], Value: fields[2]})
+}
+
+func (api *fakeAPI) add(r fakeRecord) fakeRecord {
+	api.nextID++
+	r.ID = fmt.Sprintf("r%d", api.nextID)
+	api.records = append(api.records, r)
+	return r
+}
+
+// dump returns the records of the zone with the given ID as "<type> <name>
+// <value>", in creation order.
+func (api *fakeAPI) dump(zoneID string) []string {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	var records []string
+	for _, r := range api.records {
+		if r.ZoneID == zoneID {
+			records = append(records, r.Type+" "+r.Name+" "+r.Value)
+		}
+	}
+	return records
+}
+
+func (api *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	switch {
+	case r.URL.Path == "/zones" && r.Method == http.MethodGet:
+		zones := []fakeZone{}
+		for _, zone := range api.zones {
+			if name := r.URL.Query().Get("name"); name == "" || name == zone.Name {
+				zone.RecordsCount = len(slices.DeleteFunc(slices.Clone(api.records), func(r fakeRecord) bool { return r.ZoneID != zone.ID }))
+				zones = append(zones, zone)
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"zones": zones,
+			"meta":  map[string]interface{}{"pagination": map[string]int{"page": 1, "last_page": 1}},
+		})
+	case strings.HasPrefix(r.URL.Path, "/zones/") && r.Method == http.MethodPut:
+		var update fakeZone
+		json.NewDecoder(r.Body).Decode(&update)
+		for i := range api.zones {
+			if api.zones[i].ID == strings.TrimPrefix(r.URL.Path, "/zones/") {
+				api.zones[i].TTL = update.TTL
+				json.NewEncoder(w).Encode(map[string]interface{}{"zone": api.zones[i]})
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+	case r.URL.Path == "/records" && r.Method == http.MethodGet:
+		records := []fakeRecord{}
+		for _, record := range api.records {
+			if record.ZoneID == r.URL.Query().Get("zone_id") {
+				records = append(records, record)
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"records": records})
+	case r.URL.Path == "/records" && r.Method == http.MethodPost:
+		var record fakeRecord
+		json.NewDecoder(r.Body).Decode(&record)
+		json.NewEncoder(w).Encode(map[string]interface{}{"record": api.add(record)})
+	case r.URL.Path == "/records/bulk" && r.Method == http.MethodPut:
+		var bulk struct {
+			Records []fakeRecord `json:"records"`
+		}
+		json.NewDecoder(r.Body).Decode(&bulk)
+		updated, failed := []fakeRecord{}, []fakeRecord{}
+		for _, record := range bulk.Records {
+			i := slices.IndexFunc(api.records, func(existing fakeRecord) bool { return existing.ID == record.ID })
+			if i < 0 || strings.HasPrefix(record.Value, "bad") {
+				failed = append(failed, record)
+				continue
+			}
+			api.records[i] = record
+			updated = append(updated, record)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"records": updated, "failed_records": failed})
+	case strings.HasPrefix(r.URL.Path, "/records/"):
+		id := strings.TrimPrefix(r.URL.Path, "/records/")
+		for i, existing := range api.records {
+			if existing.ID != id {
+				continue
+			}
+			switch r.Method {
+			case http.MethodGet:
+				json.NewEncoder(w).Encode(map[string]interface{}{"record": existing})
+			case http.MethodPut:
+				var record fakeRecord
+				json.NewDecoder(r.Body).Decode(&record)
+				record.ID = existing.ID
+				api.records[i] = record
+				json.NewEncoder(w).Encode(map[string]interface{}{"record": record})
+			case http.MethodDelete:
+				api.records = append(api.records[:i], api.records[i+1:]...)
+			}
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
@@ -0,0 +1,109 @@
+package hetzner
+
+import (
+	"context"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/libdns/libdns"
+)
+
+// FindOptions configures FindRecordsByValue.
+type FindOptions struct {
+	// Types restricts the search to records of these types. All types are
+	// searched if it is empty.
+	Types []string
+
+	// Substring matches records whose value contains the searched value
+	// instead of records whose value equals it. Substring matches ignore
+	// case.
+	Substring bool
+}
+
+// FindRecordsByValue returns the records in the zone whose value matches
+// value, sorted by name, type and value, e.g. to find what still points at a
+// host before it is decommissioned.
+//
+// Exact matches ignore case and a trailing dot in hostnames, and also match
+// the target of MX and SRV records, so searching for "mail.example.com"
+// finds "10 mail.example.com.".
+func (p *Provider) FindRecordsByValue(ctx context.Context, zone string, value string, opts FindOptions) ([]libdns.Record, error) {
+	records, err := p.getAllRecords(ctx, unFQDN(zone))
+	if err != nil {
+		return nil, err
+	}
+
+	var found []libdns.Record
+	for _, r := range records {
+		if len(opts.Types) > 0 && !slices.Contains(opts.Types, r.Type) {
+			continue
+		}
+		if matchesValue(r, value, opts.Substring) {
+			found = append(found, r)
+		}
+	}
+
+	sortRecords(found)
+	return found, nil
+}
+
+// matchesValue reports whether the value of r matches value, see
+// FindRecordsByValue.
+func matchesValue(r libdns.Record, value string, substring bool) bool {
+	if substring {
+		return strings.Contains(strings.ToLower(r.Value), strings.ToLower(value))
+	}
+
+	if diffValue(r) == diffValue(libdns.Record{Type: r.Type, Value: value}) {
+		return true
+	}
+
+	fields := strings.Fields(r.Value)
+	if (r.Type != "MX" && r.Type != "SRV") || len(fields) == 0 {
+		return false
+	}
+	target := fields[len(fields)-1]
+	return strings.EqualFold(strings.TrimSuffix(target, "."), strings.TrimSuffix(value, "."))
+}
+
+// FindRecordsTargeting searches all zones of the account for A, AAAA and
+// CNAME records pointing at target, an IP address or hostname, e.g. to make
+// sure nothing still uses a server before it is decommissioned. Zones are
+// searched concurrently, up to Provider.Concurrency at a time.
+//
+// It returns the matching records keyed by zone name, and the errors of the
+// zones that couldn't be searched, keyed the same way; the errors map is nil
+// if all zones were searched.
+func (p *Provider) FindRecordsTargeting(ctx context.Context, target string) (map[string][]libdns.Record, map[string]error, error) {
+	zones, err := p.ListZones(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	names := make([]string, 0, len(zones))
+	for _, z := range zones {
+		names = append(names, z.Name)
+	}
+	recordsByZone, errs := p.GetRecordsForZones(ctx, names)
+
+	addr, addrErr := netip.ParseAddr(target)
+	found := map[string][]libdns.Record{}
+	for zone, records := range recordsByZone {
+		for _, r := range records {
+			var match bool
+			switch r.Type {
+			case "A", "AAAA":
+				ip, err := netip.ParseAddr(r.Value)
+				match = addrErr == nil && err == nil && ip.Unmap() == addr.Unmap()
+			case "CNAME":
+				match = matchesValue(r, target, false)
+			}
+			if match {
+				found[zone] = append(found[zone], r)
+			}
+		}
+	}
+
+	return found, errs, nil
+}
@@ -0,0 +1,69 @@
+package hetzner_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/libdns/hetzner"
+)
+
+func Test_FindRecordsByValue(t *testing.T) {
+	p, _ := newFakeProvider(t,
+		"A www 192.0.2.1",
+		"A api 192.0.2.10",
+		"CNAME shop Web.example.net.",
+		"MX @ 10 web.example.net.",
+		"TXT note web.example.net is going away",
+	)
+
+	testCases := []struct {
+		value    string
+		opts     hetzner.FindOptions
+		expected []string
+	}{
+		{value: "192.0.2.1", expected: []string{"www"}},
+		{value: "192.0.2.1", opts: hetzner.FindOptions{Substring: true}, expected: []string{"api", "www"}},
+		{value: "web.example.net", expected: []string{"@", "shop"}},
+		{value: "web.example.net", opts: hetzner.FindOptions{Types: []string{"CNAME"}}, expected: []string{"shop"}},
+		{value: "WEB.EXAMPLE.NET", opts: hetzner.FindOptions{Substring: true}, expected: []string{"@", "note", "shop"}},
+		{value: "203.0.113.1", expected: nil},
+	}
+
+	for _, c := range testCases {
+		records, err := p.FindRecordsByValue(context.TODO(), "example.com", c.value, c.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, r := range records {
+			names = append(names, r.Name)
+		}
+		if !slices.Equal(names, c.expected) {
+			t.Fatalf("%q %+v: %v != %v", c.value, c.opts, names, c.expected)
+		}
+	}
+}
+
+func Test_FindRecordsTargeting(t *testing.T) {
+	p, api := newFakeProvider(t, "A www 192.0.2.1", "TXT www 192.0.2.1", "AAAA v6 2001:db8::1")
+	api.addZone("example.net", "CNAME legacy www.example.com.", "A web 192.0.2.2")
+
+	found, errs, err := p.FindRecordsTargeting(context.TODO(), "192.0.2.1")
+	if err != nil || errs != nil {
+		t.Fatal(err, errs)
+	}
+	if len(found) != 1 || len(found["example.com"]) != 1 || found["example.com"][0].Type != "A" {
+		t.Fatalf("unexpected records for 192.0.2.1 => %v", found)
+	}
+
+	found, _, _ = p.FindRecordsTargeting(context.TODO(), "2001:DB8:0::1")
+	if len(found["example.com"]) != 1 {
+		t.Fatalf("unexpected records for 2001:db8::1 => %v", found)
+	}
+
+	found, _, _ = p.FindRecordsTargeting(context.TODO(), "WWW.example.com")
+	if len(found) != 1 || len(found["example.net"]) != 1 || found["example.net"][0].Name != "legacy" {
+		t.Fatalf("unexpected records for www.example.com => %v", found)
+	}
+}
@@ -1,5 +1,5 @@
 module github.com/libdns/hetzner
 
-go 1.14
+go 1.23
 
 require github.com/libdns/libdns v0.1.0
@@ -0,0 +1,213 @@
+// Package hetznertest provides helpers for testing code that talks to the
+// Hetzner DNS API without a live API token.
+package hetznertest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// Mode selects whether a Recorder records or replays interactions.
+type Mode int
+
+const (
+	// ModeReplay serves responses from the fixture file and fails requests
+	// that have no recorded interaction.
+	ModeReplay Mode = iota
+
+	// ModeRecord forwards requests to the real API and records the
+	// interactions, which are written to the fixture file on Stop.
+	ModeRecord
+)
+
+// Interaction is a recorded request and its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is the recorded part of an HTTP request. Request headers are not
+// recorded, so the Auth-API-Token never ends up in a fixture.
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// Response is the recorded part of an HTTP response.
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// sensitiveHeaders are response headers never written to fixtures.
+var sensitiveHeaders = []string{"Set-Cookie", "Auth-Api-Token", "Authorization"}
+
+// Recorder is an http.RoundTripper that records API interactions to a JSON
+// fixture file and replays them offline.
+//
+// In replay mode requests are matched by method, URL and body; each
+// recorded interaction is served at most once, in recording order, so a
+// sequence of identical requests can get different responses.
+type Recorder struct {
+	// Transport is used to perform real requests in record mode. Defaults
+	// to http.DefaultTransport.
+	Transport http.RoundTripper
+
+	// Sanitize, if set, is called on every interaction before it is
+	// recorded, e.g. to replace zone names or IDs in bodies.
+	Sanitize func(*Interaction)
+
+	mode         Mode
+	path         string
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewRecorder returns a Recorder for the fixture at path. In replay mode the
+// fixture must exist.
+func NewRecorder(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{mode: mode, path: path}
+	if mode == ModeRecord {
+		return r, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &r.interactions); err != nil {
+		return nil, fmt.Errorf("reading fixture %s: %v", path, err)
+	}
+	r.used = make([]bool, len(r.interactions))
+
+	return r, nil
+}
+
+// Client returns an HTTP client using the recorder as its transport.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	recorded := Request{Method: req.Method, URL: req.URL.String(), Body: body}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+	return r.record(req, recorded)
+}
+
+func (r *Recorder) replay(req *http.Request, recorded Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.interactions {
+		if r.used[i] || interaction.Request != recorded {
+			continue
+		}
+		r.used[i] = true
+		return &http.Response{
+			Status:     fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode: interaction.Response.StatusCode,
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header:     interaction.Response.Header.Clone(),
+			Body:       ioutil.NopCloser(bytes.NewBufferString(interaction.Response.Body)),
+			Request:    req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("hetznertest: no recorded interaction for %s %s", recorded.Method, recorded.URL)
+}
+
+func (r *Recorder) record(req *http.Request, recorded Request) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	response, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	response.Body = ioutil.NopCloser(bytes.NewBuffer(data))
+
+	header := response.Header.Clone()
+	for _, name := range sensitiveHeaders {
+		header.Del(name)
+	}
+	interaction := Interaction{
+		Request:  recorded,
+		Response: Response{StatusCode: response.StatusCode, Header: header, Body: string(data)},
+	}
+	if r.Sanitize != nil {
+		r.Sanitize(&interaction)
+	}
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, interaction)
+	r.mu.Unlock()
+
+	return response, nil
+}
+
+// Stop writes the recorded interactions to the fixture file. It is a no-op
+// in replay mode.
+func (r *Recorder) Stop() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := json.MarshalIndent(r.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.path, append(data, '\n'), 0o644)
+}
+
+func readBody(req *http.Request) (string, error) {
+	if req.Body == nil {
+		return "", nil
+	}
+
+	data, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return "", err
+	}
+	req.Body.Close()
+	req.Body = ioutil.NopCloser(bytes.NewBuffer(data))
+
+	return string(data), nil
+}
+
+// ModeFromEnv returns ModeRecord if the environment variable
+// LIBDNS_HETZNER_RECORD is set to a non-empty value, ModeReplay otherwise.
+func ModeFromEnv() Mode {
+	if os.Getenv("LIBDNS_HETZNER_RECORD") != "" {
+		return ModeRecord
+	}
+	return ModeReplay
+}
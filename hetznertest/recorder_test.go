@@ -0,0 +1,72 @@
+package hetznertest_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/libdns/hetzner"
+	"github.com/libdns/hetzner/hetznertest"
+)
+
+type rewriteTransport struct {
+	target string
+}
+
+func (t rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = "http"
+	req.URL.Host = strings.TrimPrefix(t.target, "http://")
+	req.Host = req.URL.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func Test_RecordAndReplay(t *testing.T) {
+	var sawToken string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sawToken = r.Header.Get("Auth-API-Token")
+		w.Header().Set("Set-Cookie", "secret=1")
+		fmt.Fprint(w, `{"record":{"id":"1","zone_id":"z","type":"TXT","name":"test","value":"test","ttl":120}}`)
+	}))
+	defer server.Close()
+
+	fixture := filepath.Join(t.TempDir(), "fixture.json")
+
+	recorder, err := hetznertest.NewRecorder(fixture, hetznertest.ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder.Transport = rewriteTransport{target: server.URL}
+	p := &hetzner.Provider{AuthAPIToken: "secret-token", HTTPClient: recorder.Client()}
+	if _, err := p.GetRecord(context.TODO(), "1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := recorder.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if sawToken != "secret-token" {
+		t.Fatalf(`sawToken != "secret-token" => %q`, sawToken)
+	}
+	server.Close()
+
+	replayer, err := hetznertest.NewRecorder(fixture, hetznertest.ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p = &hetzner.Provider{AuthAPIToken: "unused", HTTPClient: replayer.Client()}
+	record, err := p.GetRecord(context.TODO(), "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Value != "test" {
+		t.Fatalf(`record.Value != "test" => %q`, record.Value)
+	}
+
+	if _, err := p.GetRecord(context.TODO(), "1"); err == nil {
+		t.Fatal("expected error for exhausted interaction")
+	}
+}
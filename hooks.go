@@ -0,0 +1,103 @@
+package hetzner
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/libdns/libdns"
+)
+
+// ValueTransform transforms record values written to and read from the API.
+type ValueTransform struct {
+	// Types are the record types the transform applies to. If empty, it
+	// applies to all types.
+	Types []string
+
+	// Write transforms values before they are sent to the API, Read those
+	// returned by it. Either may be nil.
+	Write func(recordType string, value string) string
+	Read  func(recordType string, value string) string
+}
+
+func (t ValueTransform) applies(recordType string) bool {
+	return len(t.Types) == 0 || slices.Contains(t.Types, recordType)
+}
+
+// LowercaseValues returns a transform lowercasing the values of records of
+// the given types in both directions, e.g. LowercaseValues("CNAME", "NS",
+// "MX") for case-insensitive targets.
+func LowercaseValues(types ...string) ValueTransform {
+	lower := func(_ string, value string) string { return strings.ToLower(value) }
+	return ValueTransform{Types: types, Write: lower, Read: lower}
+}
+
+// TrimValueSuffix returns a transform trimming suffix, e.g. an internal
+// domain like ".corp.internal", from the values of records of the given
+// types in both directions.
+func TrimValueSuffix(suffix string, types ...string) ValueTransform {
+	trim := func(_ string, value string) string { return strings.TrimSuffix(value, suffix) }
+	return ValueTransform{Types: types, Write: trim, Read: trim}
+}
+
+// outgoingRecord prepares r to be sent to the API: its name is made relative
+// to the zone, it is checked against Provider.Scope and Provider.Policy
+// unless ctx is unrestricted, Provider.OutgoingRecordHook and the write
+// transforms of Provider.ValueTransforms are applied, and the result is
+// validated.
+func (p *Provider) outgoingRecord(ctx context.Context, zone string, r libdns.Record) (libdns.Record, error) {
+	name, err := p.apiName(r, zone)
+	if err != nil {
+		return libdns.Record{}, err
+	}
+	r.Name = name
+	if !optionsFrom(ctx).unrestricted {
+		if err := p.checkScope(zone, r); err != nil {
+			return libdns.Record{}, err
+		}
+		if err := p.checkPolicy(zone, r); err != nil {
+			return libdns.Record{}, err
+		}
+	}
+
+	if p.OutgoingRecordHook != nil {
+		r = p.OutgoingRecordHook(zone, r)
+	}
+	for _, transform := range p.ValueTransforms {
+		if transform.Write != nil && transform.applies(r.Type) {
+			r.Value = transform.Write(r.Type, r.Value)
+		}
+	}
+	r.Value = canonicalValue(r.Type, r.Value)
+
+	if err := p.checkType(r); err != nil {
+		return libdns.Record{}, err
+	}
+
+	if err := ValidateRecord(r); err != nil {
+		return libdns.Record{}, err
+	}
+	return r, nil
+}
+
+// incomingRecord converts a record returned by the API and applies the read
+// transforms of Provider.ValueTransforms and Provider.IncomingRecordHook.
+func (p *Provider) incomingRecord(zone string, r APIRecord) libdns.Record {
+	result := libdns.Record{
+		ID:    r.ID,
+		Type:  r.Type,
+		Name:  r.Name,
+		Value: r.Value,
+		TTL:   ttlDuration(r.TTL),
+	}
+
+	for _, transform := range p.ValueTransforms {
+		if transform.Read != nil && transform.applies(result.Type) {
+			result.Value = transform.Read(result.Type, result.Value)
+		}
+	}
+	if p.IncomingRecordHook != nil {
+		result = p.IncomingRecordHook(zone, result)
+	}
+	return result
+}
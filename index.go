@@ -0,0 +1,73 @@
+package hetzner
+
+import (
+	"sync"
+
+	"github.com/libdns/libdns"
+)
+
+// recordIndex indexes the records of a zone by name and type, so batch
+// operations on large zones can look up existing records without fetching
+// the zone for every record. It is safe for concurrent use.
+type recordIndex struct {
+	zone string
+
+	mu     sync.Mutex
+	rrsets map[rrsetKey][]libdns.Record
+}
+
+func newRecordIndex(zone string, records []libdns.Record) *recordIndex {
+	index := &recordIndex{zone: zone, rrsets: make(map[rrsetKey][]libdns.Record, len(records))}
+	for _, record := range records {
+		key := index.key(record)
+		index.rrsets[key] = append(index.rrsets[key], record)
+	}
+	return index
+}
+
+func (x *recordIndex) key(r libdns.Record) rrsetKey {
+	return rrsetKey{name: normalizeRecordName(r.Name, x.zone), recordType: r.Type}
+}
+
+// claim returns an existing record with the same name and type as r and
+// removes it from the index, so several records of a batch never claim the
+// same existing record. A record that also has the same value is preferred.
+// It returns nil if no unclaimed record of that name and type is left.
+func (x *recordIndex) claim(r libdns.Record) *libdns.Record {
+	return x.take(r, false)
+}
+
+// claimValue is like claim, but only returns a record that also has the same
+// value as r.
+func (x *recordIndex) claimValue(r libdns.Record) *libdns.Record {
+	return x.take(r, true)
+}
+
+func (x *recordIndex) take(r libdns.Record, valueOnly bool) *libdns.Record {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	key := x.key(r)
+	rrset := x.rrsets[key]
+	if len(rrset) == 0 {
+		return nil
+	}
+
+	i := -1
+	for j, record := range rrset {
+		if sameValue(record, r) {
+			i = j
+			break
+		}
+	}
+	if i < 0 {
+		if valueOnly {
+			return nil
+		}
+		i = 0
+	}
+
+	claimed := rrset[i]
+	x.rrsets[key] = append(rrset[:i:i], rrset[i+1:]...)
+	return &claimed
+}
@@ -0,0 +1,193 @@
+//go:build integration
+
+// Package integration_test runs the whole Provider surface against a Hetzner
+// DNS API compatible mock. By default the mock runs in-process; set
+// LIBDNS_HETZNER_INTEGRATION_URL (and LIBDNS_HETZNER_INTEGRATION_ZONE, an
+// existing empty zone) to run against one started elsewhere, e.g. in a
+// container:
+//
+//	go test -tags integration ./integration
+package integration_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/libdns/hetzner"
+	"github.com/libdns/libdns"
+)
+
+// setup returns a provider and the zone to test with. The mock is nil if the
+// tests run against an external server.
+func setup(t *testing.T) (*hetzner.Provider, string, *mockAPI) {
+	t.Helper()
+
+	if url := os.Getenv("LIBDNS_HETZNER_INTEGRATION_URL"); url != "" {
+		zone := os.Getenv("LIBDNS_HETZNER_INTEGRATION_ZONE")
+		if zone == "" {
+			t.Fatal("LIBDNS_HETZNER_INTEGRATION_ZONE must be set with LIBDNS_HETZNER_INTEGRATION_URL")
+		}
+		return hetzner.New("token", hetzner.WithBaseURL(url)), zone, nil
+	}
+
+	api := newMockAPI()
+	api.addZone("example.com")
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+	return hetzner.New("token", hetzner.WithBaseURL(server.URL+"/api/v1")), "example.com", api
+}
+
+func Test_Lifecycle(t *testing.T) {
+	p, zone, _ := setup(t)
+	ctx := context.Background()
+
+	appended, err := p.AppendRecords(ctx, zone, []libdns.Record{
+		{Type: "A", Name: "www", Value: "192.0.2.1", TTL: time.Hour},
+		{Type: "AAAA", Name: "www", Value: "2001:db8::1"},
+		{Type: "MX", Name: "@", Value: "10 mail.example.org."},
+		{Type: "TXT", Name: "_acme-challenge", Value: "token"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(appended) != 4 {
+		t.Fatalf("len(appended) != 4 => %v", appended)
+	}
+
+	set, err := p.SetRecords(ctx, zone, []libdns.Record{{Type: "A", Name: "www", Value: "192.0.2.2", TTL: 5 * time.Minute}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set) != 1 || set[0].ID != appended[0].ID || set[0].TTL != 5*time.Minute {
+		t.Fatalf("unexpected updated records => %v", set)
+	}
+
+	if _, err := p.SetZoneRecordTTLs(ctx, zone, 10*time.Minute, func(r libdns.Record) bool { return r.Type == "TXT" }); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := p.DeleteRecords(ctx, zone, []libdns.Record{{Type: "MX", Name: "@", Value: "10 mail.example.org"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := p.GetRecords(ctx, zone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]time.Duration{
+		"A www 192.0.2.2":           5 * time.Minute,
+		"AAAA www 2001:db8::1":      0,
+		"TXT _acme-challenge token": 10 * time.Minute,
+	}
+	for _, r := range records {
+		key := r.Type + " " + r.Name + " " + r.Value
+		if r.Type == "SOA" || r.Type == "NS" {
+			continue
+		}
+		ttl, ok := want[key]
+		if !ok || ttl != r.TTL {
+			t.Fatalf("unexpected record %s with TTL %s", key, r.TTL)
+		}
+		delete(want, key)
+	}
+	if len(want) != 0 {
+		t.Fatalf("missing records => %v", want)
+	}
+
+	plan, err := p.SyncRecords(ctx, zone, []libdns.Record{{Type: "TXT", Name: "_acme-challenge", Value: "token"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Delete) != 2 || len(plan.Create) != 0 {
+		t.Fatalf("unexpected plan => %+v", plan)
+	}
+}
+
+func Test_Validation(t *testing.T) {
+	p, zone, _ := setup(t)
+
+	_, err := p.AppendRecords(context.Background(), zone, []libdns.Record{{Type: "SVCB", Name: "_svc", Value: "1 svc.example.com."}})
+	var validationErr *hetzner.ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "type" {
+		t.Fatalf("err != ValidationError for the type => %v", err)
+	}
+}
+
+func Test_Pagination(t *testing.T) {
+	p, zone, api := setup(t)
+	if api == nil {
+		t.Skip("needs the in-process mock to create zones")
+	}
+	ctx := context.Background()
+
+	for i := 0; i < 150; i++ {
+		api.addZone(fmt.Sprintf("zone%d.example", i))
+	}
+	zones, err := p.ListZones(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(zones) != 151 {
+		t.Fatalf("len(zones) != 151 => %d", len(zones))
+	}
+
+	p.Concurrency = 8
+	records := make([]libdns.Record, 0, 250)
+	for i := 0; i < 250; i++ {
+		records = append(records, libdns.Record{Type: "TXT", Name: fmt.Sprintf("r%d", i), Value: "v"})
+	}
+	if _, err := p.AppendRecords(ctx, zone, records); err != nil {
+		t.Fatal(err)
+	}
+
+	n := 0
+	for _, err := range p.RecordsSeq(ctx, zone) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		n++
+	}
+	if n != 254 {
+		t.Fatalf("RecordsSeq yielded %d records, not 254", n)
+	}
+}
+
+func Test_RateLimit(t *testing.T) {
+	p, zone, api := setup(t)
+	if api == nil {
+		t.Skip("needs the in-process mock to lower the rate limit")
+	}
+	api.RateLimit = 5
+	api.RateWindow = time.Second
+	p.Retry = &hetzner.RetryPolicy{MaxAttempts: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: 2 * time.Second}
+
+	for i := 0; i < 12; i++ {
+		if _, err := p.GetRecords(context.Background(), zone); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if api.requestCount() <= 12 {
+		t.Fatalf("no request was rate limited => %d requests", api.requestCount())
+	}
+
+	rl, ok := p.RateLimit()
+	if !ok || rl.Limit != 5 {
+		t.Fatalf("unexpected rate limit => %+v", rl)
+	}
+
+	p.Retry = nil
+	for {
+		_, err := p.GetRecords(context.Background(), zone)
+		if hetzner.IsRateLimited(err) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,316 @@
+//go:build integration
+
+package integration_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// mockTypes are the record types the mock accepts, like the real API.
+var mockTypes = []string{"A", "AAAA", "CAA", "CNAME", "DANE", "DS", "HINFO", "MX", "NS", "PTR", "RP", "SOA", "SRV", "TLSA", "TXT"}
+
+type mockZone struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	TTL          int    `json:"ttl"`
+	RecordsCount int    `json:"records_count"`
+}
+
+type mockRecord struct {
+	ID       string `json:"id"`
+	ZoneID   string `json:"zone_id"`
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	TTL      *int   `json:"ttl,omitempty"`
+	Created  string `json:"created"`
+	Modified string `json:"modified"`
+}
+
+// mockAPI is a Hetzner DNS API compatible server holding its state in
+// memory. Like the real API, it paginates zones and record listings that
+// ask for a page, reports its rate limit in Ratelimit-* headers and answers
+// with 429 Too Many Requests once the limit of the current window is used
+// up, and rejects invalid records with 422 Unprocessable Entity.
+type mockAPI struct {
+	// RateLimit requests are allowed per RateWindow.
+	RateLimit  int
+	RateWindow time.Duration
+
+	mu          sync.Mutex
+	zones       []mockZone
+	records     []mockRecord
+	nextID      int
+	windowStart time.Time
+	used        int
+	requests    int
+}
+
+func newMockAPI() *mockAPI {
+	return &mockAPI{RateLimit: 3600, RateWindow: time.Hour}
+}
+
+func (api *mockAPI) addZone(name string) string {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	api.nextID++
+	id := fmt.Sprintf("zone%d", api.nextID)
+	api.zones = append(api.zones, mockZone{ID: id, Name: name, TTL: 86400})
+	api.addRecord(mockRecord{ZoneID: id, Type: "SOA", Name: "@", Value: "hydrogen.ns.hetzner.com. dns.hetzner.com. 2024010101 86400 10800 3600000 3600"})
+	for _, ns := range []string{"hydrogen.ns.hetzner.com.", "oxygen.ns.hetzner.com.", "helium.ns.hetzner.de."} {
+		api.addRecord(mockRecord{ZoneID: id, Type: "NS", Name: "@", Value: ns})
+	}
+	return id
+}
+
+func (api *mockAPI) addRecord(r mockRecord) mockRecord {
+	api.nextID++
+	r.ID = fmt.Sprintf("rec%d", api.nextID)
+	r.Created = time.Now().UTC().Format("2006-01-02 15:04:05.999 -0700 MST")
+	r.Modified = r.Created
+	api.records = append(api.records, r)
+	return r
+}
+
+// requestCount returns the number of requests served, including rejected
+// ones.
+func (api *mockAPI) requestCount() int {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	return api.requests
+}
+
+func (api *mockAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	api.requests++
+
+	if r.Header.Get("Auth-API-Token") == "" {
+		writeError(w, http.StatusUnauthorized, "invalid authentication credentials")
+		return
+	}
+
+	now := time.Now()
+	if now.Sub(api.windowStart) >= api.RateWindow {
+		api.windowStart, api.used = now, 0
+	}
+	reset := api.windowStart.Add(api.RateWindow)
+	w.Header().Set("Ratelimit-Limit", strconv.Itoa(api.RateLimit))
+	w.Header().Set("Ratelimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+	if api.used >= api.RateLimit {
+		w.Header().Set("Ratelimit-Remaining", "0")
+		w.Header().Set("Retry-After", strconv.Itoa(int(time.Until(reset).Seconds())+1))
+		writeError(w, http.StatusTooManyRequests, "rate limit exceeded")
+		return
+	}
+	api.used++
+	w.Header().Set("Ratelimit-Remaining", strconv.Itoa(api.RateLimit-api.used))
+
+	switch path := strings.TrimPrefix(r.URL.Path, "/api/v1"); {
+	case path == "/zones" && r.Method == http.MethodGet:
+		api.listZones(w, r)
+	case strings.HasPrefix(path, "/zones/") && r.Method == http.MethodPut:
+		api.updateZone(w, r, strings.TrimPrefix(path, "/zones/"))
+	case path == "/records" && r.Method == http.MethodGet:
+		api.listRecords(w, r)
+	case path == "/records" && r.Method == http.MethodPost:
+		api.createRecord(w, r)
+	case path == "/records/bulk" && r.Method == http.MethodPut:
+		api.bulkUpdate(w, r)
+	case strings.HasPrefix(path, "/records/"):
+		api.record(w, r, strings.TrimPrefix(path, "/records/"))
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+// paginate returns the page of n items the request asks for as a range of
+// indexes and the pagination metadata. Requests without a page get all
+// items, capped at 100 like the real API does for zones.
+func paginate(r *http.Request, n int, defaultPerPage int) (int, int, map[string]int) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = defaultPerPage
+	}
+	lastPage := max(1, (n+perPage-1)/perPage)
+	start := min(n, (page-1)*perPage)
+	end := min(n, start+perPage)
+	return start, end, map[string]int{"page": page, "per_page": perPage, "last_page": lastPage, "total_entries": n}
+}
+
+func (api *mockAPI) listZones(w http.ResponseWriter, r *http.Request) {
+	zones := []mockZone{}
+	for _, zone := range api.zones {
+		if name := r.URL.Query().Get("name"); name != "" && name != zone.Name {
+			continue
+		}
+		zone.RecordsCount = len(api.zoneRecords(zone.ID))
+		zones = append(zones, zone)
+	}
+	if len(zones) == 0 && r.URL.Query().Has("name") {
+		writeError(w, http.StatusNotFound, "zone not found")
+		return
+	}
+
+	start, end, pagination := paginate(r, len(zones), 100)
+	writeJSON(w, http.StatusOK, map[string]interface{}{"zones": zones[start:end], "meta": map[string]interface{}{"pagination": pagination}})
+}
+
+func (api *mockAPI) updateZone(w http.ResponseWriter, r *http.Request, id string) {
+	var update mockZone
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	for i := range api.zones {
+		if api.zones[i].ID == id {
+			api.zones[i].TTL = update.TTL
+			writeJSON(w, http.StatusOK, map[string]interface{}{"zone": api.zones[i]})
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "zone not found")
+}
+
+func (api *mockAPI) zoneRecords(zoneID string) []mockRecord {
+	var records []mockRecord
+	for _, record := range api.records {
+		if record.ZoneID == zoneID {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+func (api *mockAPI) listRecords(w http.ResponseWriter, r *http.Request) {
+	records := api.zoneRecords(r.URL.Query().Get("zone_id"))
+	if records == nil {
+		records = []mockRecord{}
+	}
+
+	perPage := len(records)
+	if r.URL.Query().Has("page") {
+		perPage = 100
+	}
+	start, end, pagination := paginate(r, len(records), max(1, perPage))
+	writeJSON(w, http.StatusOK, map[string]interface{}{"records": records[start:end], "meta": map[string]interface{}{"pagination": pagination}})
+}
+
+// validate returns the message of the 422 response for an invalid record,
+// or an empty string.
+func (api *mockAPI) validate(r mockRecord) string {
+	switch {
+	case !slices.Contains(mockTypes, r.Type):
+		return "invalid type"
+	case r.Name == "":
+		return "invalid name"
+	case r.Value == "":
+		return "invalid value"
+	case r.TTL != nil && *r.TTL < 60:
+		return "invalid TTL"
+	case !slices.ContainsFunc(api.zones, func(z mockZone) bool { return z.ID == r.ZoneID }):
+		return "invalid zone_id"
+	}
+	return ""
+}
+
+func (api *mockAPI) createRecord(w http.ResponseWriter, r *http.Request) {
+	var record mockRecord
+	if err := json.NewDecoder(r.Body).Decode(&record); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if msg := api.validate(record); msg != "" {
+		writeError(w, http.StatusUnprocessableEntity, msg)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"record": api.addRecord(record)})
+}
+
+// update replaces the stored record with the ID of r and returns it.
+func (api *mockAPI) update(r mockRecord) (mockRecord, bool) {
+	for i := range api.records {
+		if api.records[i].ID == r.ID {
+			r.Created = api.records[i].Created
+			r.Modified = time.Now().UTC().Format("2006-01-02 15:04:05.999 -0700 MST")
+			api.records[i] = r
+			return r, true
+		}
+	}
+	return mockRecord{}, false
+}
+
+func (api *mockAPI) bulkUpdate(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Records []mockRecord `json:"records"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	updated, failed := []mockRecord{}, []mockRecord{}
+	for _, record := range request.Records {
+		if api.validate(record) != "" {
+			failed = append(failed, record)
+		} else if record, ok := api.update(record); ok {
+			updated = append(updated, record)
+		} else {
+			failed = append(failed, record)
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"records": updated, "failed_records": failed})
+}
+
+func (api *mockAPI) record(w http.ResponseWriter, r *http.Request, id string) {
+	i := slices.IndexFunc(api.records, func(record mockRecord) bool { return record.ID == id })
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "record not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]interface{}{"record": api.records[i]})
+	case http.MethodPut:
+		var record mockRecord
+		if err := json.NewDecoder(r.Body).Decode(&record); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if msg := api.validate(record); msg != "" {
+			writeError(w, http.StatusUnprocessableEntity, msg)
+			return
+		}
+		record.ID = id
+		record, _ = api.update(record)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"record": record})
+	case http.MethodDelete:
+		api.records = slices.Delete(api.records, i, i+1)
+		w.WriteHeader(http.StatusOK)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{"error": map[string]interface{}{"message": message, "code": status}})
+}
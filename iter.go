@@ -0,0 +1,45 @@
+package hetzner
+
+import (
+	"context"
+	"iter"
+
+	"github.com/libdns/libdns"
+)
+
+// recordsPageSize is the number of records RecordsSeq fetches per request.
+const recordsPageSize = 100
+
+// RecordsSeq returns an iterator over the records in the zone that fetches
+// them page by page, so huge zones don't have to be held in memory at once.
+// If a request fails, the error is yielded once and iteration stops. Unlike
+// GetRecords, records are yielded in the order the API returns them.
+func (p *Provider) RecordsSeq(ctx context.Context, zone string) iter.Seq2[libdns.Record, error] {
+	return func(yield func(libdns.Record, error) bool) {
+		zone := unFQDN(zone)
+		zoneID, err := p.getZoneID(ctx, zone)
+		if err != nil {
+			yield(libdns.Record{}, err)
+			return
+		}
+
+		for page := 1; ; page++ {
+			records, lastPage, err := p.getRecordsPage(ctx, zone, zoneID, page, recordsPageSize)
+			if err != nil {
+				yield(libdns.Record{}, err)
+				return
+			}
+			for _, record := range records {
+				if !p.inScope(zone, record) {
+					continue
+				}
+				if !yield(record, nil) {
+					return
+				}
+			}
+			if page >= lastPage || len(records) == 0 {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package hetzner
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// RecordInfo is a record with the provider-specific data libdns.Record has
+// no room for. It can be serialized, e.g. as JSON, to update or delete the
+// record by ID in a later process without looking it up again; see
+// WithZoneID for skipping the zone lookup too.
+type RecordInfo struct {
+	Record libdns.Record `json:"record"`
+
+	// Zone is the name of the zone, ZoneID its Hetzner ID.
+	Zone   string `json:"zone"`
+	ZoneID string `json:"zone_id"`
+
+	// Created and Modified are when Hetzner created and last modified the
+	// record, or zero if the API didn't tell.
+	Created  time.Time `json:"created"`
+	Modified time.Time `json:"modified"`
+}
+
+// Context returns ctx with the zone ID of the record, see WithZoneID.
+func (i RecordInfo) Context(ctx context.Context) context.Context {
+	return WithZoneID(ctx, i.Zone, i.ZoneID)
+}
+
+// GetRecordInfos lists the records in the zone like GetRecords, with their
+// provider-specific data.
+func (p *Provider) GetRecordInfos(ctx context.Context, zone string) ([]RecordInfo, error) {
+	raw, err := p.GetRecordsRaw(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]RecordInfo, 0, len(raw))
+	for _, r := range raw {
+		var stored APIRecord
+		if err := json.Unmarshal(r.JSON, &stored); err != nil {
+			return nil, err
+		}
+		infos = append(infos, RecordInfo{
+			Record:   r.Record,
+			Zone:     unFQDN(zone),
+			ZoneID:   stored.ZoneID,
+			Created:  parseAPITime(stored.Created),
+			Modified: parseAPITime(stored.Modified),
+		})
+	}
+
+	slices.SortFunc(infos, func(a, b RecordInfo) int { return compareRecords(a.Record, b.Record) })
+	return infos, nil
+}
+
+// apiTimeLayouts are the layouts of timestamps returned by the API, which
+// formats them like Go's time.Time.String.
+var apiTimeLayouts = []string{"2006-01-02 15:04:05.999999999 -0700 MST", time.RFC3339Nano}
+
+// parseAPITime parses a timestamp returned by the API. It returns the zero
+// time if the timestamp is empty or has an unknown format.
+func parseAPITime(s string) time.Time {
+	for _, layout := range apiTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,64 @@
+package hetzner_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libdns/hetzner"
+	"github.com/libdns/libdns"
+)
+
+func Test_RecordInfo(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		switch {
+		case r.URL.Path == "/zones":
+			fmt.Fprint(w, `{"zones":[{"id":"z","name":"example.com"}]}`)
+		case r.Method == http.MethodGet:
+			fmt.Fprint(w, `{"records":[{"id":"r1","zone_id":"z","type":"A","name":"www","value":"192.0.2.1",`+
+				`"created":"2024-01-10 12:34:56.789 +0000 UTC","modified":"2024-02-01 08:00:00 +0000 UTC"}]}`)
+		default:
+			fmt.Fprint(w, testRecordResponse)
+		}
+	})
+
+	infos, err := p.GetRecordInfos(context.TODO(), "example.com.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 1 || infos[0].ZoneID != "z" || infos[0].Zone != "example.com" || infos[0].Record.ID != "r1" {
+		t.Fatalf("unexpected infos => %+v", infos)
+	}
+	if !infos[0].Created.Equal(time.Date(2024, 1, 10, 12, 34, 56, 789e6, time.UTC)) || infos[0].Modified.IsZero() {
+		t.Fatalf("unexpected timestamps => %v, %v", infos[0].Created, infos[0].Modified)
+	}
+
+	data, err := json.Marshal(infos[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var info hetzner.RecordInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	requests = nil
+	mu.Unlock()
+	if _, err := p.DeleteRecords(info.Context(context.TODO()), info.Zone, []libdns.Record{info.Record}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(requests, ",") != "GET /records/r1,DELETE /records/r1" {
+		t.Fatalf("unexpected requests => %v", requests)
+	}
+}
@@ -0,0 +1,34 @@
+package hetzner
+
+import (
+	"context"
+
+	"github.com/libdns/libdns"
+)
+
+// GetRecordsForZones fetches the records of several zones concurrently, up to
+// Provider.Concurrency at a time. It returns the records of every zone that
+// could be fetched, keyed by zone name as given, and the errors of the zones
+// that couldn't. The errors map is nil if all zones were fetched.
+func (p *Provider) GetRecordsForZones(ctx context.Context, zones []string) (map[string][]libdns.Record, map[string]error) {
+	records := make([][]libdns.Record, len(zones))
+	errs := make([]error, len(zones))
+	forEachConcurrently(len(zones), p.concurrency(), func(i int) {
+		records[i], errs[i] = p.GetRecords(ctx, zones[i])
+	})
+
+	recordsByZone := make(map[string][]libdns.Record, len(zones))
+	var errsByZone map[string]error
+	for i, zone := range zones {
+		if errs[i] != nil {
+			if errsByZone == nil {
+				errsByZone = map[string]error{}
+			}
+			errsByZone[zone] = errs[i]
+			continue
+		}
+		recordsByZone[zone] = records[i]
+	}
+
+	return recordsByZone, errsByZone
+}
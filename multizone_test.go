@@ -0,0 +1,30 @@
+package hetzner_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/libdns/hetzner"
+)
+
+func Test_GetRecordsForZones(t *testing.T) {
+	p, api := newFakeProvider(t, "A www 192.0.2.1", "TXT @ hello")
+	api.addZone("example.org", "A www 192.0.2.2")
+	p.Concurrency = 2
+
+	records, errs := p.GetRecordsForZones(context.TODO(), []string{"example.com", "example.org.", "missing.example"})
+	if len(records) != 2 || len(records["example.com"]) != 2 || len(records["example.org."]) != 1 {
+		t.Fatalf("unexpected records => %v", records)
+	}
+	if records["example.org."][0].Value != "192.0.2.2" {
+		t.Fatalf("records of the wrong zone => %v", records["example.org."])
+	}
+	if len(errs) != 1 || !errors.Is(errs["missing.example"], hetzner.ErrZoneNotFound) {
+		t.Fatalf("unexpected errors => %v", errs)
+	}
+
+	if _, errs := p.GetRecordsForZones(context.TODO(), []string{"example.com"}); errs != nil {
+		t.Fatalf("errs != nil => %v", errs)
+	}
+}
@@ -0,0 +1,100 @@
+package hetzner
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/libdns/libdns"
+)
+
+// RelativeName returns name relative to zone, exactly as this package sends
+// it to the Hetzner API: the name is lowercased, trailing dots and the zone
+// suffix are trimmed, and the zone apex is returned as "@".
+func RelativeName(name string, zone string) string {
+	return normalizeRecordName(name, unFQDN(zone))
+}
+
+// AbsoluteName returns the fully-qualified name, with trailing dot, of a
+// record name as returned by this package for the given zone. "@" and the
+// empty name denote the zone apex.
+func AbsoluteName(name string, zone string) string {
+	zone = unFQDN(zone)
+	relative := RelativeName(name, zone)
+	if relative == "@" {
+		return zone + "."
+	}
+	return relative + "." + zone + "."
+}
+
+func normalizeRecordName(recordName string, zone string) string {
+	// Workaround for https://github.com/caddy-dns/hetzner/issues/3
+	// Can be removed after https://github.com/libdns/libdns/issues/12
+	normalized := strings.ToLower(unFQDN(recordName))
+	zone = strings.ToLower(unFQDN(zone))
+	if normalized == zone {
+		normalized = ""
+	} else if zone != "" {
+		// only trim whole labels, so "*.myexample.com" stays intact in
+		// zone "example.com"
+		normalized = strings.TrimSuffix(normalized, "."+zone)
+	}
+	if normalized == "" {
+		// Hetzner represents the zone apex as "@"
+		return "@"
+	}
+	return normalized
+}
+
+// apiName returns the name of r as sent to the API, applying
+// Provider.StrictNames.
+func (p *Provider) apiName(r libdns.Record, zone string) (string, error) {
+	if !p.StrictNames {
+		return normalizeRecordName(r.Name, zone), nil
+	}
+
+	switch {
+	case r.Name == "":
+		return "", fmt.Errorf("%w: %s record has an empty name, use \"@\" for the zone apex", ErrInvalidRecord, r.Type)
+	case strings.HasSuffix(r.Name, "."):
+		return "", fmt.Errorf("%w: %s record %q has an absolute name, names must be relative to zone %s", ErrInvalidRecord, r.Type, r.Name, zone)
+	}
+	return strings.ToLower(r.Name), nil
+}
+
+// IsReverseZone reports whether zone is a reverse DNS zone below in-addr.arpa
+// or ip6.arpa.
+func IsReverseZone(zone string) bool {
+	zone = strings.ToLower(unFQDN(zone))
+	for _, suffix := range []string{"in-addr.arpa", "ip6.arpa"} {
+		if zone == suffix || strings.HasSuffix(zone, "."+suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsWildcard reports whether name is a wildcard name like "*" or "*.sub".
+func IsWildcard(name string) bool {
+	return name == "*" || strings.HasPrefix(name, "*.")
+}
+
+// wildcardName returns the relative wildcard name covering the children of
+// parent, e.g. "*.sub" for "sub" and "*" for the zone apex.
+func wildcardName(parent string, zone string) string {
+	parent = normalizeRecordName(parent, zone)
+	if parent == "@" {
+		return "*"
+	}
+	return "*." + parent
+}
+
+// sameName reports whether the record names a and b, relative to zone or
+// absolute, denote the same name. DNS names are case-insensitive.
+func sameName(a string, b string, zone string) bool {
+	return normalizeRecordName(a, zone) == normalizeRecordName(b, zone)
+}
+
+// unFQDN trims any trailing "." from fqdn. Hetzner's API does not use FQDNs.
+func unFQDN(fqdn string) string {
+	return strings.TrimSuffix(fqdn, ".")
+}
@@ -0,0 +1,53 @@
+package hetzner_test
+
+import (
+	"testing"
+
+	"github.com/libdns/hetzner"
+)
+
+func Test_RelativeName(t *testing.T) {
+	testCases := []struct {
+		name     string
+		zone     string
+		expected string
+	}{
+		{name: "www", zone: "example.com", expected: "www"},
+		{name: "www.example.com", zone: "example.com", expected: "www"},
+		{name: "www.example.com.", zone: "example.com.", expected: "www"},
+		{name: "a.b", zone: "example.com", expected: "a.b"},
+		{name: "WWW.Example.COM.", zone: "example.com", expected: "www"},
+		{name: "*", zone: "example.com", expected: "*"},
+		{name: "*.example.com.", zone: "example.com", expected: "*"},
+		{name: "*.sub.example.com", zone: "example.com", expected: "*.sub"},
+		{name: "*.myexample.com", zone: "example.com", expected: "*.myexample.com"},
+		{name: "example.com.", zone: "example.com", expected: "@"},
+		{name: "@", zone: "example.com", expected: "@"},
+		{name: "", zone: "example.com", expected: "@"},
+	}
+
+	for _, c := range testCases {
+		if got := hetzner.RelativeName(c.name, c.zone); got != c.expected {
+			t.Fatalf("RelativeName(%q, %q) != %q => %q", c.name, c.zone, c.expected, got)
+		}
+	}
+}
+
+func Test_AbsoluteName(t *testing.T) {
+	testCases := []struct {
+		name     string
+		zone     string
+		expected string
+	}{
+		{name: "www", zone: "example.com", expected: "www.example.com."},
+		{name: "www.example.com.", zone: "example.com", expected: "www.example.com."},
+		{name: "@", zone: "example.com.", expected: "example.com."},
+		{name: "*.sub", zone: "example.com", expected: "*.sub.example.com."},
+	}
+
+	for _, c := range testCases {
+		if got := hetzner.AbsoluteName(c.name, c.zone); got != c.expected {
+			t.Fatalf("AbsoluteName(%q, %q) != %q => %q", c.name, c.zone, c.expected, got)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package hetzner
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Option configures a Provider created with New.
+type Option func(*Provider)
+
+// New returns a Provider for the given Auth-API-Token configured by opts.
+func New(token string, opts ...Option) *Provider {
+	p := &Provider{AuthAPIToken: token}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// WithHTTPClient sets the HTTP client used for API requests.
+func WithHTTPClient(client *http.Client) Option {
+	return func(p *Provider) {
+		p.HTTPClient = client
+	}
+}
+
+// WithBaseURL points the provider at a different API endpoint, e.g. a proxy
+// or a test server. It defaults to https://dns.hetzner.com/api/v1.
+func WithBaseURL(baseURL string) Option {
+	return func(p *Provider) {
+		p.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithRetry sets the retry policy for failed API requests.
+func WithRetry(policy RetryPolicy) Option {
+	return func(p *Provider) {
+		p.Retry = &policy
+	}
+}
+
+// WithLogger sets the logger for warnings.
+func WithLogger(logger Logger) Option {
+	return func(p *Provider) {
+		p.Logger = logger
+	}
+}
+
+// WithCache caches zone IDs for ttl in Provider.Cache, saving a zone lookup
+// request for every operation on a zone.
+func WithCache(ttl time.Duration) Option {
+	return func(p *Provider) {
+		p.zoneIDs = newZoneCache("zone-id:", ttl, p.cache)
+	}
+}
+
+// WithNegativeCache remembers for ttl that a zone doesn't exist, so callers
+// retrying operations on a missing zone in a tight loop fail with
+// ErrZoneNotFound without a zone lookup request every time. Use a short ttl,
+// as creating the zone only takes effect after it, or clear the entry with
+// ForgetMissingZone.
+func WithNegativeCache(ttl time.Duration) Option {
+	return func(p *Provider) {
+		p.missingZones = newZoneCache("missing-zone:", ttl, p.cache)
+	}
+}
@@ -0,0 +1,276 @@
+package hetzner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/libdns/libdns"
+)
+
+// ErrNotOwned is returned when Provider.OwnerID is set and a record to be
+// changed or deleted is owned by another owner, see Provider.OwnerID.
+var ErrNotOwned = errors.New("record is owned by another owner")
+
+// ownershipHeritage starts the values of ownership registry records.
+const ownershipHeritage = "heritage=libdns-hetzner,owner="
+
+// defaultOwnershipPrefix is the default of Provider.OwnershipPrefix.
+const defaultOwnershipPrefix = "_owner"
+
+// registry tracks the ownership of the RRsets of a zone, as recorded by
+// companion TXT records named "<prefix>.<type>.<name>", e.g. "_owner.a.www"
+// for the A records of www and "_owner.mx" for the MX records of the apex.
+// Their value names the owner, "heritage=libdns-hetzner,owner=<id>".
+type registry struct {
+	zone   string
+	prefix string
+	owner  string
+
+	mu      sync.Mutex
+	entries map[rrsetKey]libdns.Record
+	counts  map[rrsetKey]int
+}
+
+func (p *Provider) newRegistry(zone string) *registry {
+	prefix := p.OwnershipPrefix
+	if prefix == "" {
+		prefix = defaultOwnershipPrefix
+	}
+	return &registry{
+		zone:    zone,
+		prefix:  strings.ToLower(strings.Trim(prefix, ".")),
+		owner:   p.OwnerID,
+		entries: map[rrsetKey]libdns.Record{},
+		counts:  map[rrsetKey]int{},
+	}
+}
+
+// parse returns the RRset and owner a registry record describes, and false
+// if r is not a registry record.
+func (reg *registry) parse(r libdns.Record) (rrsetKey, string, bool) {
+	owner, ok := strings.CutPrefix(r.Value, ownershipHeritage)
+	if r.Type != "TXT" || !ok {
+		return rrsetKey{}, "", false
+	}
+	rest, ok := strings.CutPrefix(normalizeRecordName(r.Name, reg.zone), reg.prefix+".")
+	if !ok {
+		return rrsetKey{}, "", false
+	}
+	recordType, name, _ := strings.Cut(rest, ".")
+	if name == "" {
+		name = "@"
+	}
+	return rrsetKey{name: name, recordType: strings.ToUpper(recordType)}, owner, true
+}
+
+// entry returns the registry record claiming the RRset for reg.owner.
+func (reg *registry) entry(key rrsetKey) libdns.Record {
+	name := reg.prefix + "." + strings.ToLower(key.recordType)
+	if key.name != "@" {
+		name += "." + key.name
+	}
+	return libdns.Record{Type: "TXT", Name: name, Value: ownershipHeritage + reg.owner}
+}
+
+func (reg *registry) key(r libdns.Record) rrsetKey {
+	return rrsetKey{name: normalizeRecordName(r.Name, reg.zone), recordType: r.Type}
+}
+
+// owns reports whether the RRset of r is owned by reg.owner.
+func (reg *registry) owns(r libdns.Record) bool {
+	reg.mu.Lock()
+	entry, ok := reg.entries[reg.key(r)]
+	reg.mu.Unlock()
+	if !ok {
+		return false
+	}
+	_, owner, _ := reg.parse(entry)
+	return owner == reg.owner
+}
+
+// add adds a record of the zone to the registry.
+func (reg *registry) add(r libdns.Record) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	if key, _, ok := reg.parse(r); ok {
+		reg.entries[key] = r
+	} else {
+		reg.counts[reg.key(r)]++
+	}
+}
+
+// check returns an error wrapping ErrNotOwned if r belongs to an RRset, or
+// is the registry record of an RRset, owned by another owner. RRsets without
+// a registry record aren't owned by anyone and can be changed.
+func (reg *registry) check(ctx context.Context, r libdns.Record) error {
+	if reg == nil || optionsFrom(ctx).force {
+		return nil
+	}
+
+	key, owner, isEntry := reg.parse(r)
+	if !isEntry {
+		key = reg.key(r)
+		reg.mu.Lock()
+		entry, ok := reg.entries[key]
+		reg.mu.Unlock()
+		if !ok {
+			return nil
+		}
+		_, owner, _ = reg.parse(entry)
+	}
+
+	if owner != reg.owner {
+		return fmt.Errorf("%w: %s record %q belongs to %q", ErrNotOwned, key.recordType, key.name, owner)
+	}
+	return nil
+}
+
+// claim records that r was created, or updated if created is false, and
+// creates the registry record of its RRset if there is none yet.
+func (p *Provider) claim(ctx context.Context, reg *registry, r libdns.Record, created bool) error {
+	if reg == nil {
+		return nil
+	}
+
+	if key, _, ok := reg.parse(r); ok {
+		reg.mu.Lock()
+		reg.entries[key] = r
+		reg.mu.Unlock()
+		return nil
+	}
+
+	key := reg.key(r)
+	reg.mu.Lock()
+	if created {
+		reg.counts[key]++
+	}
+	_, owned := reg.entries[key]
+	reg.mu.Unlock()
+	if owned {
+		return nil
+	}
+
+	if _, err := p.createRecord(unrestricted(ctx), reg.zone, reg.entry(key)); err != nil {
+		return fmt.Errorf("recording ownership of %s record %q: %w", r.Type, r.Name, err)
+	}
+	return nil
+}
+
+// release records that r was deleted, and deletes the registry record of its
+// RRset once the last record owned by reg.owner is gone.
+func (p *Provider) release(ctx context.Context, reg *registry, r libdns.Record) error {
+	if reg == nil {
+		return nil
+	}
+
+	if key, _, ok := reg.parse(r); ok {
+		reg.mu.Lock()
+		delete(reg.entries, key)
+		reg.mu.Unlock()
+		return nil
+	}
+
+	key := reg.key(r)
+	reg.mu.Lock()
+	reg.counts[key]--
+	entry, owned := reg.entries[key]
+	remaining := reg.counts[key]
+	reg.mu.Unlock()
+	if !owned || remaining > 0 {
+		return nil
+	}
+	if _, owner, _ := reg.parse(entry); owner != reg.owner {
+		return nil
+	}
+
+	if err := p.deleteRecord(unrestricted(ctx), reg.zone, entry); err != nil {
+		return fmt.Errorf("removing ownership of %s record %q: %w", r.Type, r.Name, err)
+	}
+	return nil
+}
+
+type registryKey struct{}
+
+// registry returns the ownership registry of the zone, or nil if
+// Provider.OwnerID isn't set. A registry loaded with withRegistry for the
+// call is reused, otherwise the zone's records are fetched.
+func (p *Provider) registry(ctx context.Context, zone string) (*registry, error) {
+	if p.OwnerID == "" {
+		return nil, nil
+	}
+	if reg, ok := ctx.Value(registryKey{}).(*registry); ok && reg.zone == zone {
+		return reg, nil
+	}
+
+	// the registry records of scoped records are usually outside the scope
+	records, err := p.getAllRecords(unrestricted(ctx), zone)
+	if err != nil {
+		return nil, err
+	}
+	reg := p.newRegistry(zone)
+	for _, r := range records {
+		reg.add(r)
+	}
+	return reg, nil
+}
+
+// withRegistry loads the ownership registry of the zone once for all
+// changes of a batch operation using the returned context.
+func (p *Provider) withRegistry(ctx context.Context, zone string) (context.Context, error) {
+	reg, err := p.registry(ctx, zone)
+	if err != nil || reg == nil {
+		return ctx, err
+	}
+	return context.WithValue(ctx, registryKey{}, reg), nil
+}
+
+// withoutRegistryRecords returns the records that aren't ownership registry
+// records, which are managed by the provider itself. It returns records as
+// is if Provider.OwnerID isn't set.
+func (p *Provider) withoutRegistryRecords(zone string, records []libdns.Record) []libdns.Record {
+	if p.OwnerID == "" {
+		return records
+	}
+
+	reg := p.newRegistry(zone)
+	var filtered []libdns.Record
+	for _, r := range records {
+		if _, _, ok := reg.parse(r); !ok {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
+
+// ownedOnly splits current into the records owned by Provider.OwnerID,
+// which SyncRecords may update and delete with Provider.SyncOwnedOnly, and
+// removes the desired records that already exist unowned, with values
+// compared as by Diff, so they aren't created a second time.
+func (p *Provider) ownedOnly(zone string, current []libdns.Record, desired []libdns.Record) ([]libdns.Record, []libdns.Record) {
+	reg := p.newRegistry(zone)
+	for _, r := range current {
+		reg.add(r)
+	}
+
+	var owned []libdns.Record
+	unowned := map[libdns.Record]bool{}
+	for _, r := range current {
+		if reg.owns(r) {
+			owned = append(owned, r)
+		} else {
+			unowned[libdns.Record{Type: r.Type, Name: normalizeRecordName(r.Name, zone), Value: diffValue(r)}] = true
+		}
+	}
+
+	var wanted []libdns.Record
+	for _, r := range desired {
+		if !unowned[libdns.Record{Type: r.Type, Name: normalizeRecordName(r.Name, zone), Value: diffValue(r)}] {
+			wanted = append(wanted, r)
+		}
+	}
+	return owned, wanted
+}
@@ -0,0 +1,114 @@
+package hetzner_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/libdns/hetzner"
+	"github.com/libdns/libdns"
+)
+
+func Test_Ownership(t *testing.T) {
+	p, api := newFakeProvider(t, "A manual 192.0.2.9")
+	p.OwnerID = "a"
+
+	_, err := p.AppendRecords(context.TODO(), "example.com", []libdns.Record{
+		{Type: "A", Name: "www", Value: "192.0.2.1"},
+		{Type: "A", Name: "www", Value: "192.0.2.2"},
+		{Type: "MX", Name: "@", Value: "10 mail.example.com."},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	records := api.dump("z")
+	if !slices.Contains(records, "TXT _owner.a.www heritage=libdns-hetzner,owner=a") || !slices.Contains(records, "TXT _owner.mx heritage=libdns-hetzner,owner=a") || len(records) != 6 {
+		t.Fatalf("unexpected records => %v", records)
+	}
+
+	other, _ := p.LookupRecordID(context.TODO(), "example.com", "www", "A", "192.0.2.1")
+	q := newTestProvider(t, api.ServeHTTP)
+	q.OwnerID = "b"
+	_, err = q.DeleteRecords(context.TODO(), "example.com", []libdns.Record{{ID: other}})
+	if !errors.Is(err, hetzner.ErrNotOwned) {
+		t.Fatalf("err != ErrNotOwned => %v", err)
+	}
+	if _, err := q.DeleteRecords(context.TODO(), "example.com", []libdns.Record{{Type: "A", Name: "manual", Value: "192.0.2.9", ID: "r1"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// syncing leaves the registry records alone and removes them along with
+	// the last record they own
+	plan, err := p.SyncRecords(context.TODO(), "example.com", []libdns.Record{{Type: "A", Name: "www", Value: "192.0.2.1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Delete) != 2 {
+		t.Fatalf("len(plan.Delete) != 2 => %v", plan.Delete)
+	}
+	records = api.dump("z")
+	if !slices.Equal(records, []string{"A www 192.0.2.1", "TXT _owner.a.www heritage=libdns-hetzner,owner=a"}) {
+		t.Fatalf("unexpected records => %v", records)
+	}
+
+	if _, err := q.DeleteRecords(hetzner.WithForce(context.TODO()), "example.com", []libdns.Record{{ID: other}}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_SyncOwnedOnly(t *testing.T) {
+	p, api := newFakeProvider(t, "A manual 192.0.2.9", "A www 192.0.2.8", "CNAME docs pages.example.org.")
+	p.OwnerID = "a"
+	p.SyncOwnedOnly = true
+
+	desired := []libdns.Record{{Type: "A", Name: "www", Value: "192.0.2.8"}, {Type: "A", Name: "api", Value: "192.0.2.1"}, {Type: "CNAME", Name: "docs", Value: "Pages.example.org"}}
+	if _, err := p.SyncRecords(context.TODO(), "example.com", desired); err != nil {
+		t.Fatal(err)
+	}
+	plan, err := p.SyncRecords(context.TODO(), "example.com", []libdns.Record{{Type: "A", Name: "www", Value: "192.0.2.8"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Create) != 0 || len(plan.Update) != 0 || len(plan.Delete) != 1 || plan.Delete[0].Name != "api" {
+		t.Fatalf("unexpected plan => %+v", plan)
+	}
+	if records := api.dump("z"); !slices.Equal(records, []string{"A manual 192.0.2.9", "A www 192.0.2.8", "CNAME docs pages.example.org."}) {
+		t.Fatalf("unexpected records => %v", records)
+	}
+}
+
+func Test_OwnershipScopeAndPolicy(t *testing.T) {
+	p, api := newFakeProvider(t)
+	p.OwnerID = "a"
+	p.Scope = &hetzner.Scope{NamePrefix: "dev-"}
+
+	added, err := p.AppendRecords(context.TODO(), "example.com", []libdns.Record{{Type: "A", Name: "dev-api", Value: "192.0.2.1"}})
+	if err != nil || len(added) != 1 {
+		t.Fatalf("unexpected result => %v, %v", added, err)
+	}
+	if records := api.dump("z"); !slices.Equal(records, []string{"A dev-api 192.0.2.1", "TXT _owner.a.dev-api heritage=libdns-hetzner,owner=a"}) {
+		t.Fatalf("unexpected records => %v", records)
+	}
+	if _, err := p.DeleteRecords(context.TODO(), "example.com", added); err != nil {
+		t.Fatal(err)
+	}
+	if records := api.dump("z"); len(records) != 0 {
+		t.Fatalf("registry record left behind => %v", records)
+	}
+
+	p.Scope = nil
+	p.Policy = &hetzner.Policy{Allow: []hetzner.PolicyRule{{Types: []string{"TXT"}, Names: []string{"_acme-challenge", "_acme-challenge.*"}}}}
+	if _, err := p.AppendRecords(context.TODO(), "example.com", []libdns.Record{{Type: "TXT", Name: "_acme-challenge", Value: "token"}}); err != nil {
+		t.Fatal(err)
+	}
+	if records := api.dump("z"); !slices.Equal(records, []string{"TXT _acme-challenge token", "TXT _owner.txt._acme-challenge heritage=libdns-hetzner,owner=a"}) {
+		t.Fatalf("unexpected records => %v", records)
+	}
+
+	// callers still can't write registry-looking records past the policy
+	_, err = p.AppendRecords(context.TODO(), "example.com", []libdns.Record{{Type: "TXT", Name: "_owner.a.www", Value: "heritage=libdns-hetzner,owner=a"}})
+	if !errors.Is(err, hetzner.ErrPolicyViolation) {
+		t.Fatalf("err != ErrPolicyViolation => %v", err)
+	}
+}
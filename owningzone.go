@@ -0,0 +1,62 @@
+package hetzner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/libdns/libdns"
+)
+
+// OwningZone finds the zone of the account a fully qualified name belongs to,
+// i.e. the most specific zone the name is in, and returns it along with the
+// name relative to it. It returns an error wrapping ErrZoneNotFound if no
+// zone of the account contains the name.
+func (p *Provider) OwningZone(ctx context.Context, fqdn string) (zone string, name string, err error) {
+	zones, err := p.getAllZones(ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	zone, ok := owningZone(zones, fqdn)
+	if !ok {
+		return "", "", zoneNotFound(unFQDN(fqdn))
+	}
+	return zone, normalizeRecordName(fqdn, zone), nil
+}
+
+// GroupByZone resolves the owning zone of records with fully qualified names
+// as OwningZone does, and returns them grouped by zone with names relative
+// to it, ready to be passed to e.g. SetRecords. The zones are listed once
+// for all records.
+func (p *Provider) GroupByZone(ctx context.Context, records []libdns.Record) (map[string][]libdns.Record, error) {
+	zones, err := p.getAllZones(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	grouped := map[string][]libdns.Record{}
+	for _, record := range records {
+		zone, ok := owningZone(zones, record.Name)
+		if !ok {
+			return nil, fmt.Errorf("%s record %q: %w", record.Type, record.Name, zoneNotFound(unFQDN(record.Name)))
+		}
+		record.Name = normalizeRecordName(record.Name, zone)
+		grouped[zone] = append(grouped[zone], record)
+	}
+	return grouped, nil
+}
+
+// owningZone returns the name of the most specific zone containing fqdn.
+func owningZone(zones []Zone, fqdn string) (string, bool) {
+	name := strings.ToLower(unFQDN(fqdn))
+
+	best := ""
+	for _, z := range zones {
+		zone := strings.ToLower(unFQDN(z.Name))
+		if (name == zone || strings.HasSuffix(name, "."+zone)) && len(zone) > len(best) {
+			best = zone
+		}
+	}
+	return best, best != ""
+}
@@ -0,0 +1,160 @@
+package hetzner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+
+	"github.com/libdns/libdns"
+)
+
+// ErrPolicyViolation is returned when Provider.Policy doesn't permit a
+// change to a record. Nothing is sent to the API then.
+var ErrPolicyViolation = errors.New("record not permitted by policy")
+
+// Policy constrains which records the provider may create, update and
+// delete, e.g. to limit a token shared by many applications to ACME
+// challenges:
+//
+//	Policy{Allow: []PolicyRule{{Types: []string{"TXT"}, Names: []string{"_acme-challenge", "_acme-challenge.*"}}}}
+//
+// A record is permitted if it matches none of the Deny rules and, unless
+// Allow is empty, at least one of the Allow rules. Updates and deletions must
+// be permitted for the record as it is stored as well as, for updates, the
+// record it becomes. Reads are not restricted.
+type Policy struct {
+	Allow []PolicyRule `json:"allow,omitempty"`
+	Deny  []PolicyRule `json:"deny,omitempty"`
+}
+
+// PolicyRule matches records by type, name and value. A record matches if it
+// satisfies all the conditions that are set; a rule without conditions
+// matches every record.
+type PolicyRule struct {
+	// Types are the record types the rule matches.
+	Types []string `json:"types,omitempty"`
+
+	// Names are glob patterns, see path.Match, for names relative to the
+	// zone, with "@" for the apex. "*" matches across dots, so
+	// "_acme-challenge.*" covers the challenges of all subdomains.
+	Names []string `json:"names,omitempty"`
+
+	// NameRegexp and ValueRegexp are regular expressions the relative name
+	// and the value must match.
+	NameRegexp  string `json:"name_regexp,omitempty"`
+	ValueRegexp string `json:"value_regexp,omitempty"`
+}
+
+// validate returns an error for malformed patterns of the policy.
+func (pol *Policy) validate() error {
+	var errs []error
+	for _, rule := range slices.Concat(pol.Allow, pol.Deny) {
+		if _, err := rule.matches("", "", "", regexp.Compile); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// permits reports whether the policy permits the record with the given type,
+// relative name and value. Regular expressions are compiled with compile.
+func (pol *Policy) permits(recordType string, name string, value string, compile func(string) (*regexp.Regexp, error)) (bool, error) {
+	for _, rule := range pol.Deny {
+		if match, err := rule.matches(recordType, name, value, compile); err != nil || match {
+			return false, err
+		}
+	}
+	if len(pol.Allow) == 0 {
+		return true, nil
+	}
+	for _, rule := range pol.Allow {
+		if match, err := rule.matches(recordType, name, value, compile); err != nil || match {
+			return match, err
+		}
+	}
+	return false, nil
+}
+
+// matches reports whether the rule matches the record. All patterns are
+// checked for syntax errors even if an earlier condition doesn't match.
+func (rule PolicyRule) matches(recordType string, name string, value string, compile func(string) (*regexp.Regexp, error)) (bool, error) {
+	match := len(rule.Types) == 0 || slices.Contains(rule.Types, recordType)
+
+	if len(rule.Names) > 0 {
+		var nameMatch bool
+		for _, pattern := range rule.Names {
+			ok, err := path.Match(pattern, name)
+			if err != nil {
+				return false, fmt.Errorf("policy name pattern %q: %w", pattern, err)
+			}
+			nameMatch = nameMatch || ok
+		}
+		match = match && nameMatch
+	}
+
+	for _, c := range []struct{ field, pattern, s string }{
+		{"name", rule.NameRegexp, name},
+		{"value", rule.ValueRegexp, value},
+	} {
+		if c.pattern == "" {
+			continue
+		}
+		re, err := compile(c.pattern)
+		if err != nil {
+			return false, fmt.Errorf("policy %s pattern %q: %w", c.field, c.pattern, err)
+		}
+		match = match && re.MatchString(c.s)
+	}
+
+	return match, nil
+}
+
+// checkPolicy returns an error wrapping ErrPolicyViolation if Provider.Policy
+// doesn't permit r in the zone.
+func (p *Provider) checkPolicy(zone string, r libdns.Record) error {
+	if p.Policy == nil {
+		return nil
+	}
+
+	name := normalizeRecordName(r.Name, zone)
+	ok, err := p.Policy.permits(r.Type, name, r.Value, p.policyRegexp)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	if !ok {
+		return fmt.Errorf("%w: %s record %q with value %q", ErrPolicyViolation, r.Type, name, r.Value)
+	}
+	return nil
+}
+
+// policyRegexp compiles a regular expression of Provider.Policy, once per
+// pattern, so the policy isn't recompiled for every record.
+func (p *Provider) policyRegexp(pattern string) (*regexp.Regexp, error) {
+	if re, ok := p.policyRegexps.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	p.policyRegexps.Store(pattern, re)
+	return re, nil
+}
+
+// checkStored fetches the record with the given ID and checks that
+// Provider.Scope and Provider.Policy permit changing it, so records can't be
+// modified by ID while passing the checks with made-up fields.
+func (p *Provider) checkStored(ctx context.Context, zone string, id string) error {
+	if p.Policy == nil && p.Scope == nil || optionsFrom(ctx).unrestricted {
+		return nil
+	}
+
+	stored, err := p.getRecord(ctx, id)
+	if err != nil {
+		return err
+	}
+	return p.checkPolicy(zone, stored)
+}
@@ -0,0 +1,52 @@
+package hetzner_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/libdns/hetzner"
+	"github.com/libdns/libdns"
+)
+
+func Test_Policy(t *testing.T) {
+	p, api := newFakeProvider(t, "A www 192.0.2.1", "TXT _acme-challenge.www old")
+	p.Policy = &hetzner.Policy{
+		Allow: []hetzner.PolicyRule{{Types: []string{"TXT"}, Names: []string{"_acme-challenge", "_acme-challenge.*"}}},
+		Deny:  []hetzner.PolicyRule{{ValueRegexp: "^forbidden"}},
+	}
+
+	testCases := []struct {
+		record  libdns.Record
+		allowed bool
+	}{
+		{record: libdns.Record{Type: "TXT", Name: "_acme-challenge", Value: "token"}, allowed: true},
+		{record: libdns.Record{Type: "TXT", Name: "_acme-challenge.sub.example.com.", Value: "token"}, allowed: true},
+		{record: libdns.Record{Type: "TXT", Name: "_acme-challenge", Value: "forbidden"}},
+		{record: libdns.Record{Type: "TXT", Name: "www", Value: "token"}},
+		{record: libdns.Record{Type: "A", Name: "_acme-challenge", Value: "192.0.2.1"}},
+	}
+	for _, c := range testCases {
+		_, err := p.AppendRecords(context.TODO(), "example.com", []libdns.Record{c.record})
+		if c.allowed != (err == nil) || (err != nil && !errors.Is(err, hetzner.ErrPolicyViolation)) {
+			t.Fatalf("%+v: allowed != %v => %v", c.record, c.allowed, err)
+		}
+	}
+
+	// the stored record is checked too, not only the fields given
+	_, err := p.DeleteRecords(context.TODO(), "example.com", []libdns.Record{{ID: "r1", Type: "TXT", Name: "_acme-challenge", Value: "token"}})
+	if !errors.Is(err, hetzner.ErrPolicyViolation) {
+		t.Fatalf("err != ErrPolicyViolation => %v", err)
+	}
+	if _, err := p.DeleteRecords(context.TODO(), "example.com", []libdns.Record{{ID: "r2"}}); err != nil {
+		t.Fatal(err)
+	}
+	if records := api.dump("z"); len(records) != 3 || records[0] != "A www 192.0.2.1" {
+		t.Fatalf("unexpected records => %v", records)
+	}
+
+	p.Policy.Deny = []hetzner.PolicyRule{{NameRegexp: "("}}
+	if err := p.Validate(); !errors.Is(err, hetzner.ErrInvalidConfig) {
+		t.Fatalf("err != ErrInvalidConfig => %v", err)
+	}
+}
@@ -62,18 +62,25 @@ func TestMain(m *testing.M) {
 	envToken = os.Getenv("LIBDNS_HETZNER_TEST_TOKEN")
 	envZone = os.Getenv("LIBDNS_HETZNER_TEST_ZONE")
 
+	os.Exit(m.Run())
+}
+
+// skipWithoutLiveAPI skips tests running against the public Hetzner DNS API
+// unless a token and zone are given.
+func skipWithoutLiveAPI(t *testing.T) {
+	t.Helper()
+
 	if len(envToken) == 0 || len(envZone) == 0 {
-		fmt.Println(`Please notice that this test runs agains the public Hetzner DNS Api, so you sould
+		t.Skip(`Please notice that this test runs agains the public Hetzner DNS Api, so you sould
 never run the test with a zone, used in production.
 To run this test, you have to specify 'LIBDNS_HETZNER_TEST_TOKEN' and 'LIBDNS_HETZNER_TEST_ZONE'.
 Example: "LIBDNS_HETZNER_TEST_TOKEN="123" LIBDNS_HETZNER_TEST_ZONE="my-domain.com" go test ./... -v`)
-		os.Exit(1)
 	}
-
-	os.Exit(m.Run())
 }
 
 func Test_AppendRecords(t *testing.T) {
+	skipWithoutLiveAPI(t)
+
 	p := &hetzner.Provider{
 		AuthAPIToken: envToken,
 	}
@@ -158,6 +165,8 @@ func Test_AppendRecords(t *testing.T) {
 }
 
 func Test_DeleteRecords(t *testing.T) {
+	skipWithoutLiveAPI(t)
+
 	p := &hetzner.Provider{
 		AuthAPIToken: envToken,
 	}
@@ -189,6 +198,8 @@ func Test_DeleteRecords(t *testing.T) {
 }
 
 func Test_GetRecords(t *testing.T) {
+	skipWithoutLiveAPI(t)
+
 	p := &hetzner.Provider{
 		AuthAPIToken: envToken,
 	}
@@ -220,6 +231,8 @@ func Test_GetRecords(t *testing.T) {
 }
 
 func Test_SetRecords(t *testing.T) {
+	skipWithoutLiveAPI(t)
+
 	p := &hetzner.Provider{
 		AuthAPIToken: envToken,
 	}
@@ -0,0 +1,78 @@
+package hetzner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrQuotaExceeded is wrapped by QuotaError.
+var ErrQuotaExceeded = errors.New("record quota exceeded")
+
+// QuotaError is returned when adding records would take a zone past
+// Provider.MaxRecordsPerZone. Nothing was changed.
+type QuotaError struct {
+	Zone  string
+	Limit int
+
+	// Current is the number of records in the zone, Adding the number of
+	// records the refused operation would have added.
+	Current int
+	Adding  int
+}
+
+func (e *QuotaError) Error() string {
+	return fmt.Sprintf("zone %s has %d records, adding %d would exceed the limit of %d", e.Zone, e.Current, e.Adding, e.Limit)
+}
+
+func (e *QuotaError) Unwrap() error {
+	return ErrQuotaExceeded
+}
+
+// checkQuota returns a *QuotaError if adding records to the zone would exceed
+// Provider.MaxRecordsPerZone. The zone's record count is looked up only if
+// there is a limit and records are added.
+func (p *Provider) checkQuota(ctx context.Context, zone string, adding int) error {
+	if p.MaxRecordsPerZone <= 0 || adding <= 0 {
+		return nil
+	}
+
+	z, err := p.getZone(ctx, zone)
+	if err != nil {
+		return err
+	}
+	if z.RecordsCount+adding > p.MaxRecordsPerZone {
+		return &QuotaError{Zone: zone, Limit: p.MaxRecordsPerZone, Current: z.RecordsCount, Adding: adding}
+	}
+	return nil
+}
+
+// Limits describes the capacity of the account, as far as it is known.
+type Limits struct {
+	// Zones is the number of zones in the account.
+	Zones int
+
+	// MaxRecordsPerZone is Provider.MaxRecordsPerZone. The API doesn't
+	// report per-zone or per-account limits, so it is 0, for unknown,
+	// unless configured.
+	MaxRecordsPerZone int
+
+	// RateLimit is the rate limit most recently reported by the API, see
+	// Provider.RateLimit, or nil if it never sent rate limit headers.
+	RateLimit *RateLimit
+}
+
+// Limits queries the API for the limits it reports, with a single request,
+// for capacity planning and pre-flight checks.
+func (p *Provider) Limits(ctx context.Context) (Limits, error) {
+	_, pagination, err := p.api().ListZones(ctx, "", 1, 1)
+	if err != nil {
+		return Limits{}, err
+	}
+
+	limits := Limits{Zones: pagination.TotalEntries, MaxRecordsPerZone: p.MaxRecordsPerZone}
+	if rl, ok := p.RateLimit(); ok {
+		limits.RateLimit = &rl
+	}
+	return limits, nil
+}
@@ -0,0 +1,64 @@
+package hetzner_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/libdns/hetzner"
+	"github.com/libdns/libdns"
+)
+
+func Test_MaxRecordsPerZone(t *testing.T) {
+	p, api := newFakeProvider(t, "A www 192.0.2.1", "A mail 192.0.2.2")
+	p.MaxRecordsPerZone = 3
+
+	_, err := p.AppendRecords(context.TODO(), "example.com", []libdns.Record{
+		{Type: "TXT", Name: "a", Value: "1"},
+		{Type: "TXT", Name: "b", Value: "2"},
+	})
+	var quotaErr *hetzner.QuotaError
+	if !errors.As(err, &quotaErr) || !errors.Is(err, hetzner.ErrQuotaExceeded) {
+		t.Fatalf("err != QuotaError => %v", err)
+	}
+	if quotaErr.Current != 2 || quotaErr.Adding != 2 || quotaErr.Limit != 3 {
+		t.Fatalf("unexpected quota error => %+v", quotaErr)
+	}
+	if records := api.dump("z"); len(records) != 2 {
+		t.Fatalf("unexpected records => %v", records)
+	}
+
+	if _, err := p.AppendRecords(context.TODO(), "example.com", []libdns.Record{{Type: "TXT", Name: "a", Value: "1"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = p.SyncRecords(context.TODO(), "example.com", []libdns.Record{
+		{Type: "TXT", Name: "a", Value: "1"},
+		{Type: "TXT", Name: "c", Value: "3"},
+	})
+	if !errors.Is(err, hetzner.ErrQuotaExceeded) {
+		t.Fatalf("err != ErrQuotaExceeded => %v", err)
+	}
+}
+
+func Test_Limits(t *testing.T) {
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Ratelimit-Limit", "3600")
+		w.Header().Set("Ratelimit-Remaining", "3599")
+		fmt.Fprint(w, `{"zones":[{"id":"z","name":"example.com"}],"meta":{"pagination":{"page":1,"last_page":7,"total_entries":7}}}`)
+	})
+	p.MaxRecordsPerZone = 1000
+
+	limits, err := p.Limits(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if limits.Zones != 7 || limits.MaxRecordsPerZone != 1000 {
+		t.Fatalf("unexpected limits => %+v", limits)
+	}
+	if limits.RateLimit == nil || limits.RateLimit.Limit != 3600 || limits.RateLimit.Remaining != 3599 {
+		t.Fatalf("unexpected rate limit => %+v", limits.RateLimit)
+	}
+}
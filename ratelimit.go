@@ -0,0 +1,70 @@
+package hetzner
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit is the rate limit state reported by the Hetzner API in the
+// Ratelimit-Limit, Ratelimit-Remaining and Ratelimit-Reset response headers.
+type RateLimit struct {
+	// Limit is the number of requests allowed per window.
+	Limit int
+
+	// Remaining is the number of requests left in the current window.
+	Remaining int
+
+	// Reset is when the current window ends.
+	Reset time.Time
+
+	// Observed is when the response carrying these values was received.
+	Observed time.Time
+}
+
+// rateLimitState holds the most recently observed rate limit.
+type rateLimitState struct {
+	mu   sync.Mutex
+	last *RateLimit
+}
+
+// RateLimit returns the rate limit reported with the most recent API
+// response. The second return value is false if no response with rate limit
+// headers has been received yet.
+func (p *Provider) RateLimit() (RateLimit, bool) {
+	p.rateLimit.mu.Lock()
+	defer p.rateLimit.mu.Unlock()
+
+	if p.rateLimit.last == nil {
+		return RateLimit{}, false
+	}
+	return *p.rateLimit.last, true
+}
+
+// observeRateLimit records the rate limit headers of a response and returns
+// them, or nil if the response had none.
+func (p *Provider) observeRateLimit(header http.Header) *RateLimit {
+	limit, err := strconv.Atoi(header.Get("Ratelimit-Limit"))
+	if err != nil {
+		return nil
+	}
+
+	now := time.Now()
+	rl := &RateLimit{Limit: limit, Observed: now}
+	rl.Remaining, _ = strconv.Atoi(header.Get("Ratelimit-Remaining"))
+	if reset, err := strconv.ParseInt(header.Get("Ratelimit-Reset"), 10, 64); err == nil {
+		// Either a Unix timestamp or the number of seconds until the reset.
+		if reset > 1e9 {
+			rl.Reset = time.Unix(reset, 0)
+		} else {
+			rl.Reset = now.Add(time.Duration(reset) * time.Second)
+		}
+	}
+
+	p.rateLimit.mu.Lock()
+	p.rateLimit.last = rl
+	p.rateLimit.mu.Unlock()
+
+	return rl
+}
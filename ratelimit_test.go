@@ -0,0 +1,47 @@
+package hetzner_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_RateLimit(t *testing.T) {
+	reset := "30"
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Ratelimit-Limit", "3600")
+		w.Header().Set("Ratelimit-Remaining", "42")
+		w.Header().Set("Ratelimit-Reset", reset)
+		fmt.Fprint(w, `{"zones":[{"id":"z","name":"example.com"}]}`)
+	})
+
+	if _, ok := p.RateLimit(); ok {
+		t.Fatal("expected no rate limit before the first response")
+	}
+
+	before := time.Now()
+	if _, err := p.ListZones(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	rl, ok := p.RateLimit()
+	if !ok {
+		t.Fatal("expected a rate limit")
+	}
+	if rl.Limit != 3600 || rl.Remaining != 42 {
+		t.Fatalf("unexpected rate limit => %+v", rl)
+	}
+	if rl.Observed.Before(before) || rl.Reset.Sub(rl.Observed) != 30*time.Second {
+		t.Fatalf("unexpected reset => %+v", rl)
+	}
+
+	// a Unix timestamp instead of seconds until the reset
+	reset = "1900000000"
+	if _, err := p.ListZones(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	if rl, _ := p.RateLimit(); !rl.Reset.Equal(time.Unix(1900000000, 0)) {
+		t.Fatalf("rl.Reset != 1900000000 => %v", rl.Reset)
+	}
+}
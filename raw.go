@@ -0,0 +1,75 @@
+package hetzner
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/libdns/libdns"
+)
+
+// RawRecord is a record as stored by Hetzner next to its libdns view.
+type RawRecord struct {
+	// JSON is the record object as the API returned it, before any
+	// transforms or hooks, including fields APIRecord doesn't know. With a
+	// custom Provider.API it is the APIRecord encoded as JSON.
+	JSON json.RawMessage
+
+	// Record is the record as GetRecords returns it, after
+	// Provider.ValueTransforms and Provider.IncomingRecordHook.
+	Record libdns.Record
+}
+
+// GetRecordsRaw lists the records in the zone in the order the API returns
+// them, each with the JSON of the record as stored, e.g. to debug values
+// changed by transforms or hooks. Records outside Provider.Scope are left
+// out.
+func (p *Provider) GetRecordsRaw(ctx context.Context, zone string) ([]RawRecord, error) {
+	zone = unFQDN(zone)
+
+	zoneID, err := p.getZoneID(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	raws, err := p.listRecordsRaw(ctx, zoneID)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]RawRecord, 0, len(raws))
+	for _, raw := range raws {
+		var r APIRecord
+		if err := json.Unmarshal(raw, &r); err != nil {
+			return nil, err
+		}
+		view := p.incomingRecord(zone, r)
+		if p.inScope(zone, view) {
+			records = append(records, RawRecord{JSON: raw, Record: view})
+		}
+	}
+
+	return records, nil
+}
+
+// listRecordsRaw returns the JSON objects of the records of the zone with
+// the given ID, encoding them if the backend doesn't keep them.
+func (p *Provider) listRecordsRaw(ctx context.Context, zoneID string) ([]json.RawMessage, error) {
+	api := p.api()
+	if lister, ok := api.(rawRecordLister); ok {
+		return lister.listRecordsRaw(ctx, zoneID)
+	}
+
+	result, _, err := api.ListRecords(ctx, zoneID, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	raws := make([]json.RawMessage, 0, len(result))
+	for _, r := range result {
+		raw, err := json.Marshal(r)
+		if err != nil {
+			return nil, err
+		}
+		raws = append(raws, raw)
+	}
+	return raws, nil
+}
@@ -0,0 +1,58 @@
+package hetzner_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/libdns/hetzner"
+)
+
+func Test_GetRecordsRaw(t *testing.T) {
+	p, _ := newFakeProvider(t, "CNAME www Target.Example.org.")
+	p.ValueTransforms = []hetzner.ValueTransform{hetzner.LowercaseValues("CNAME")}
+
+	records, err := p.GetRecordsRaw(context.TODO(), "example.com.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("len(records) != 1 => %v", records)
+	}
+	if !strings.Contains(string(records[0].JSON), `"value":"Target.Example.org."`) {
+		t.Fatalf("unexpected JSON => %s", records[0].JSON)
+	}
+	if records[0].Record.Value != "target.example.org." || records[0].Record.ID == "" {
+		t.Fatalf("unexpected record => %+v", records[0].Record)
+	}
+}
+
+func Test_GetRecordsRawUnknownFields(t *testing.T) {
+	const record = `{"id":"r1","zone_id":"z","type":"A","name":"www","value":"192.0.2.1","ttl":300,"protected":true}`
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/zones":
+			fmt.Fprint(w, `{"zones":[{"id":"z","name":"example.com"}]}`)
+		case "/records":
+			fmt.Fprint(w, `{"records":[`+record+`]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	records, err := p.GetRecordsRaw(context.TODO(), "example.com.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("len(records) != 1 => %v", records)
+	}
+	if string(records[0].JSON) != record {
+		t.Fatalf("records[0].JSON != record => %s", records[0].JSON)
+	}
+	if records[0].Record.Value != "192.0.2.1" || records[0].Record.ID != "r1" {
+		t.Fatalf("unexpected record => %+v", records[0].Record)
+	}
+}
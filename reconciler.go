@@ -0,0 +1,171 @@
+package hetzner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// StateSource returns the desired state of the zones a Reconciler manages.
+type StateSource func(ctx context.Context) ([]State, error)
+
+// FileSource returns a StateSource reading a JSON file on every call, so the
+// desired state can be changed while a Reconciler runs. The file holds a
+// State, as written by ImportState, or an array of them.
+func FileSource(path string) StateSource {
+	return func(ctx context.Context) ([]State, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var states []State
+		if data = bytes.TrimSpace(data); len(data) > 0 && data[0] == '[' {
+			err = json.Unmarshal(data, &states)
+		} else {
+			var state State
+			err = json.Unmarshal(data, &state)
+			states = []State{state}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return states, nil
+	}
+}
+
+// ReconcileStatus is the state of a zone managed by a Reconciler.
+type ReconcileStatus struct {
+	Zone string
+
+	// LastCheck is when the zone was last compared to its desired state,
+	// and LastApply when drift was last corrected.
+	LastCheck time.Time
+	LastApply time.Time
+
+	// Drift are the changes found by the last check. They were applied
+	// unless Err is set or the zone was corrected less than
+	// MinApplyInterval before.
+	Drift Plan
+
+	// Err is the error of the last check or correction, if any.
+	Err error
+}
+
+// Reconciler continuously converges zones to their desired state, turning
+// the provider into a small DNS controller: every Interval it reads the
+// desired state from Source, compares every zone to it and corrects drift
+// with SyncRecords, so the provider's safety settings like
+// MaxChangesPerApply and SyncOwnedOnly apply.
+type Reconciler struct {
+	Provider *Provider
+	Source   StateSource
+
+	// Interval is the time between two checks. Jitter, if positive, adds a
+	// random delay of up to Jitter to every interval.
+	Interval time.Duration
+	Jitter   time.Duration
+
+	// MinApplyInterval, if positive, is the minimum time between two
+	// corrections of a zone. Drift found earlier is reported but only
+	// corrected by a later check, which limits the rate of changes if
+	// something else keeps changing the zone.
+	MinApplyInterval time.Duration
+
+	// OnStatus, if set, is called with the status of every zone after it
+	// was checked.
+	OnStatus func(ReconcileStatus)
+
+	mu     sync.Mutex
+	status map[string]ReconcileStatus
+}
+
+// Run reconciles immediately and then every Interval, plus jitter, until
+// ctx is done, and returns the context's error. Failures are reported via
+// Status and OnStatus.
+func (r *Reconciler) Run(ctx context.Context) error {
+	if r.Interval <= 0 {
+		return fmt.Errorf("%w: Reconciler.Interval must be positive", ErrInvalidConfig)
+	}
+
+	for {
+		r.ReconcileOnce(ctx)
+
+		delay := r.Interval
+		if r.Jitter > 0 {
+			delay += time.Duration(rand.Int63n(int64(r.Jitter)))
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// ReconcileOnce reads the desired state and reconciles every zone once. It
+// returns the error of the source or those of the zones, joined.
+func (r *Reconciler) ReconcileOnce(ctx context.Context) error {
+	states, err := r.Source(ctx)
+	if err != nil {
+		return fmt.Errorf("reading desired state: %w", err)
+	}
+
+	var errs []error
+	for _, state := range states {
+		status := r.reconcile(ctx, state)
+		if status.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", status.Zone, status.Err))
+		}
+		if r.OnStatus != nil {
+			r.OnStatus(status)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Reconciler) reconcile(ctx context.Context, state State) ReconcileStatus {
+	zone := unFQDN(state.Zone)
+
+	r.mu.Lock()
+	status := r.status[zone]
+	r.mu.Unlock()
+	status.Zone = zone
+	status.LastCheck = time.Now()
+
+	desired := state.LibdnsRecords()
+	status.Drift, status.Err = r.Provider.PlanSync(ctx, zone, desired)
+	if status.Err == nil && !status.Drift.Empty() &&
+		(r.MinApplyInterval <= 0 || time.Since(status.LastApply) >= r.MinApplyInterval) {
+		status.Drift, status.Err = r.Provider.SyncRecords(ctx, zone, desired)
+		if status.Err == nil {
+			status.LastApply = time.Now()
+		}
+	}
+
+	r.mu.Lock()
+	if r.status == nil {
+		r.status = map[string]ReconcileStatus{}
+	}
+	r.status[zone] = status
+	r.mu.Unlock()
+	return status
+}
+
+// Status returns the status of every zone checked so far, sorted by zone.
+func (r *Reconciler) Status() []ReconcileStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	statuses := make([]ReconcileStatus, 0, len(r.status))
+	for _, status := range r.status {
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Zone < statuses[j].Zone })
+	return statuses
+}
@@ -0,0 +1,47 @@
+package hetzner_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/libdns/hetzner"
+)
+
+func Test_Reconciler(t *testing.T) {
+	p, api := newFakeProvider(t, "A www 192.0.2.1", "A old 192.0.2.9")
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(path, []byte(`{"zone":"example.com","records":[{"name":"www","type":"A","value":"192.0.2.1"},{"name":"api","type":"A","value":"192.0.2.2"}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var statuses []hetzner.ReconcileStatus
+	r := &hetzner.Reconciler{
+		Provider:         p,
+		Source:           hetzner.FileSource(path),
+		MinApplyInterval: time.Hour,
+		OnStatus:         func(status hetzner.ReconcileStatus) { statuses = append(statuses, status) },
+	}
+	if err := r.ReconcileOnce(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	if records := api.dump("z"); !slices.Equal(records, []string{"A www 192.0.2.1", "A api 192.0.2.2"}) {
+		t.Fatalf("unexpected records => %v", records)
+	}
+
+	// drift within MinApplyInterval is only reported
+	api.addRecord("z", "A manual 192.0.2.3")
+	if err := r.ReconcileOnce(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 2 || statuses[1].Drift.Len() != 1 || !statuses[1].LastApply.Equal(statuses[0].LastApply) {
+		t.Fatalf("unexpected statuses => %+v", statuses)
+	}
+	if status := r.Status(); len(status) != 1 || status[0].Zone != "example.com" {
+		t.Fatalf("unexpected status => %+v", status)
+	}
+}
@@ -0,0 +1,77 @@
+package hetzner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/libdns/libdns"
+)
+
+// RenameRecord moves the record to newName, keeping its type, value and TTL,
+// and returns the record under its new name. If the record has no ID, the
+// record with the same name, type and value is renamed.
+//
+// Hetzner can't rename records in place, so the record is recreated under
+// the new name first and the old record is only deleted once the new one
+// could be read back. Both names resolve for a moment, but neither is ever
+// without the record. If deleting the old record fails, the new record is
+// returned along with the error.
+func (p *Provider) RenameRecord(ctx context.Context, zone string, old libdns.Record, newName string) (libdns.Record, error) {
+	zone = unFQDN(zone)
+	ctx, err := p.withRegistry(ctx, zone)
+	if err != nil {
+		return libdns.Record{}, err
+	}
+
+	if len(old.ID) == 0 {
+		id, err := p.LookupRecordID(ctx, zone, old.Name, old.Type, old.Value)
+		if err != nil {
+			return libdns.Record{}, err
+		}
+		old.ID = id
+	}
+
+	current, err := p.getRecord(ctx, old.ID)
+	if err != nil {
+		return libdns.Record{}, err
+	}
+	if err := p.checkDangerousStored(zone, current); err != nil {
+		return libdns.Record{}, err
+	}
+	reg, err := p.registry(ctx, zone)
+	if err != nil {
+		return libdns.Record{}, err
+	}
+	if err := reg.check(ctx, current); err != nil {
+		return libdns.Record{}, err
+	}
+
+	renamed := current
+	renamed.ID = ""
+	renamed.Name = newName
+	if renamed.TTL == 0 {
+		renamed.TTL = InheritZoneTTL
+	}
+
+	created, err := p.createRecord(ctx, zone, renamed)
+	if err != nil {
+		return libdns.Record{}, err
+	}
+
+	check, err := p.getRecord(ctx, created.ID)
+	if err == nil && (check.Type != created.Type || check.Value != created.Value) {
+		err = fmt.Errorf("renamed %s record %q reads back with value %q instead of %q", check.Type, newName, check.Value, created.Value)
+	}
+	if err != nil {
+		if cleanupErr := p.deleteRecord(ctx, zone, created); cleanupErr != nil {
+			p.logf("could not remove record %s after failed rename: %v", created.ID, cleanupErr)
+		}
+		return libdns.Record{}, err
+	}
+
+	if err := p.deleteRecord(ctx, zone, current); err != nil {
+		return created, err
+	}
+
+	return created, nil
+}
@@ -0,0 +1,31 @@
+package hetzner_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/libdns/hetzner"
+	"github.com/libdns/libdns"
+)
+
+func Test_RenameRecord(t *testing.T) {
+	p, api := newFakeProvider(t, "A www 192.0.2.1", "A www 192.0.2.2")
+
+	renamed, err := p.RenameRecord(context.TODO(), "example.com.", libdns.Record{Type: "A", Name: "www", Value: "192.0.2.2"}, "web.example.com.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if renamed.Name != "web" || renamed.Value != "192.0.2.2" {
+		t.Fatalf("unexpected renamed record => %+v", renamed)
+	}
+	if records := api.dump("z"); !slices.Equal(records, []string{"A www 192.0.2.1", "A web 192.0.2.2"}) {
+		t.Fatalf("unexpected records => %v", records)
+	}
+
+	_, err = p.RenameRecord(context.TODO(), "example.com", libdns.Record{Type: "A", Name: "mail", Value: "192.0.2.3"}, "smtp")
+	if !errors.Is(err, hetzner.ErrRecordNotFound) {
+		t.Fatalf("err != ErrRecordNotFound => %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package hetzner
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// The batch operations a ResumeToken can continue.
+const (
+	batchOpAppend = "append"
+	batchOpSet    = "set"
+	batchOpDelete = "delete"
+)
+
+// nearDeadline reports whether the deadline of ctx is less than
+// Provider.BatchDeadlineMargin away. It never is without a margin.
+func (p *Provider) nearDeadline(ctx context.Context) bool {
+	margin := p.BatchDeadlineMargin
+	deadline, ok := ctx.Deadline()
+	return ok && margin > 0 && time.Until(deadline) < margin
+}
+
+// ResumeToken describes the work a batch operation like SetRecords couldn't
+// finish before its context was done or its deadline was near. Pass it to
+// Provider.Resume to continue, possibly in another process: it can be
+// stored as a string with String and read back with ParseResumeToken.
+type ResumeToken struct {
+	// Op is the operation to continue, "append", "set" or "delete".
+	Op      string          `json:"op"`
+	Zone    string          `json:"zone"`
+	Records []libdns.Record `json:"records"`
+}
+
+// String encodes the token as an opaque string.
+func (t ResumeToken) String() string {
+	data, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// ParseResumeToken decodes a token encoded with ResumeToken.String.
+func ParseResumeToken(s string) (ResumeToken, error) {
+	var t ResumeToken
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err == nil {
+		err = json.Unmarshal(data, &t)
+	}
+	if err != nil {
+		return ResumeToken{}, fmt.Errorf("malformed resume token: %w", err)
+	}
+	return t, nil
+}
+
+// IncompleteError is returned, joined with the errors of the records, when a
+// batch operation stopped before processing all records because its context
+// was done or its deadline was near.
+type IncompleteError struct {
+	Token ResumeToken
+}
+
+func (e *IncompleteError) Error() string {
+	return fmt.Sprintf("%d records left to %s in zone %s", len(e.Token.Records), e.Token.Op, e.Token.Zone)
+}
+
+// Resume continues the batch operation of the token with the records it
+// didn't process, and returns them as that operation does.
+func (p *Provider) Resume(ctx context.Context, token ResumeToken) ([]libdns.Record, error) {
+	switch token.Op {
+	case batchOpAppend:
+		return p.AppendRecords(ctx, token.Zone, token.Records)
+	case batchOpSet:
+		return p.SetRecords(ctx, token.Zone, token.Records)
+	case batchOpDelete:
+		return p.DeleteRecords(ctx, token.Zone, token.Records)
+	default:
+		return nil, fmt.Errorf("malformed resume token: unknown operation %q", token.Op)
+	}
+}
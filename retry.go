@@ -0,0 +1,124 @@
+package hetzner
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed API requests are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts per request, including
+	// the first one. Values below 2 disable retries.
+	MaxAttempts int `json:"max_attempts,omitempty"`
+
+	// BaseDelay is the delay before the first retry; it doubles with every
+	// further attempt up to MaxDelay. Default 500ms and 10s.
+	BaseDelay time.Duration `json:"base_delay,omitempty"`
+	MaxDelay  time.Duration `json:"max_delay,omitempty"`
+
+	// Jitter randomizes each delay by up to this fraction of it, e.g. 0.2
+	// for ±20%, so concurrent clients don't retry in lockstep.
+	Jitter float64 `json:"jitter,omitempty"`
+
+	// RetryableStatusCodes are the HTTP status codes that are retried.
+	// Defaults to 429, 500, 502, 503 and 504.
+	RetryableStatusCodes []int `json:"retryable_status_codes,omitempty"`
+
+	// RetryNetworkErrors retries requests that failed without a response,
+	// e.g. because of a reset connection or a per-operation timeout.
+	RetryNetworkErrors bool `json:"retry_network_errors,omitempty"`
+
+	// RetryNonIdempotent also retries POST requests on errors other than
+	// 429. This may create duplicate records if a request was processed but
+	// its response got lost.
+	RetryNonIdempotent bool `json:"retry_non_idempotent,omitempty"`
+}
+
+var defaultRetryableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// retryPolicy returns the provider's retry policy with defaults applied.
+func (p *Provider) retryPolicy() RetryPolicy {
+	var policy RetryPolicy
+	if p.Retry != nil {
+		policy = *p.Retry
+	}
+	if policy.BaseDelay <= 0 {
+		policy.BaseDelay = 500 * time.Millisecond
+	}
+	if policy.MaxDelay <= 0 {
+		policy.MaxDelay = 10 * time.Second
+	}
+	if policy.RetryableStatusCodes == nil {
+		policy.RetryableStatusCodes = defaultRetryableStatusCodes
+	}
+	return policy
+}
+
+// retryable reports whether a request with the method that failed with err
+// may be retried.
+func (policy RetryPolicy) retryable(method string, err error) bool {
+	if errors.Is(err, ErrCircuitOpen) {
+		return false
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return policy.RetryNetworkErrors && (method != http.MethodPost || policy.RetryNonIdempotent)
+	}
+
+	if method == http.MethodPost && !policy.RetryNonIdempotent && apiErr.StatusCode != http.StatusTooManyRequests {
+		return false
+	}
+	for _, code := range policy.RetryableStatusCodes {
+		if apiErr.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// delay returns how long to wait before the attempt after the given one. A
+// Retry-After header takes precedence over the exponential backoff.
+func (policy RetryPolicy) delay(attempt int, err error) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.retryAfter > 0 {
+		return apiErr.retryAfter
+	}
+
+	delay := policy.BaseDelay
+	for i := 1; i < attempt && delay < policy.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > policy.MaxDelay {
+		delay = policy.MaxDelay
+	}
+
+	if policy.Jitter > 0 {
+		delay += time.Duration((rand.Float64()*2 - 1) * policy.Jitter * float64(delay))
+	}
+	return delay
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an
+// HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
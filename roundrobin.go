@@ -0,0 +1,44 @@
+package hetzner
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// SetRoundRobin converges the A and AAAA records of name in the zone to
+// exactly the given addresses: missing addresses are added, others are
+// removed, and duplicates in ips are ignored. Addresses are compared in
+// canonical form, so "2001:db8:0::1" matches an existing "2001:db8::1". If
+// ttl is not zero, the records get that TTL. It returns the plan that was
+// applied.
+func (p *Provider) SetRoundRobin(ctx context.Context, zone string, name string, ips []string, ttl time.Duration) (Plan, error) {
+	zone = unFQDN(zone)
+
+	seen := map[netip.Addr]bool{}
+	var desired []libdns.Record
+	for _, ip := range ips {
+		addr, err := netip.ParseAddr(ip)
+		if err != nil {
+			return Plan{}, fmt.Errorf("%w: %q is not an IP address", ErrInvalidRecord, ip)
+		}
+		addr = addr.Unmap()
+		if seen[addr] {
+			continue
+		}
+		seen[addr] = true
+
+		recordType := "A"
+		if addr.Is6() {
+			recordType = "AAAA"
+		}
+		desired = append(desired, libdns.Record{Type: recordType, Name: name, Value: addr.String(), TTL: ttl})
+	}
+
+	return p.syncRRsets(ctx, zone, desired, func(r libdns.Record) bool {
+		return (r.Type == "A" || r.Type == "AAAA") && sameName(r.Name, name, zone)
+	})
+}
@@ -0,0 +1,39 @@
+package hetzner_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/libdns/hetzner"
+)
+
+func Test_SetRoundRobin(t *testing.T) {
+	p, api := newFakeProvider(t, "A www 192.0.2.1", "A www 192.0.2.2", "AAAA www 2001:db8::1", "A api 192.0.2.1")
+
+	plan, err := p.SetRoundRobin(context.TODO(), "example.com", "www", []string{"192.0.2.2", "192.0.2.3", "2001:db8:0::1", "192.0.2.3"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Len() != 1 {
+		t.Fatalf("plan.Len() != 1 => %+v", plan)
+	}
+	records := api.dump("z")
+	slices.Sort(records)
+	if !slices.Equal(records, []string{"A api 192.0.2.1", "A www 192.0.2.2", "A www 192.0.2.3", "AAAA www 2001:db8::1"}) {
+		t.Fatalf("unexpected records => %v", records)
+	}
+
+	if _, err := p.SetRoundRobin(context.TODO(), "example.com", "www", []string{"192.0.2.2"}, 5*time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if records := api.dump("z"); !slices.Equal(records, []string{"A www 192.0.2.2", "A api 192.0.2.1"}) {
+		t.Fatalf("unexpected records => %v", records)
+	}
+
+	if _, err := p.SetRoundRobin(context.TODO(), "example.com", "www", []string{"www.example.com"}, 0); !errors.Is(err, hetzner.ErrInvalidRecord) {
+		t.Fatalf("err != ErrInvalidRecord => %v", err)
+	}
+}
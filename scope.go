@@ -0,0 +1,63 @@
+package hetzner
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/libdns/libdns"
+)
+
+// ErrOutOfScope is returned for writes to records outside of Provider.Scope.
+var ErrOutOfScope = errors.New("record is outside of the provider's scope")
+
+// Scope restricts a provider to a slice of each zone, e.g. to hand the
+// records of one team or service to its own provider instance. Names are
+// relative to the zone. If both fields are set, records must satisfy both.
+type Scope struct {
+	// Subdomain limits the provider to the name itself and the names below
+	// it, e.g. "team-a" covers "team-a" and "www.team-a".
+	Subdomain string `json:"subdomain,omitempty"`
+
+	// NamePrefix limits the provider to names starting with the prefix,
+	// e.g. "dev-" covers "dev-api" and "dev-www.team-a".
+	NamePrefix string `json:"name_prefix,omitempty"`
+}
+
+// contains reports whether the relative, lowercase name is in scope.
+func (s *Scope) contains(name string) bool {
+	if sub := strings.ToLower(unFQDN(s.Subdomain)); sub != "" && name != sub && !strings.HasSuffix(name, "."+sub) {
+		return false
+	}
+	return strings.HasPrefix(name, strings.ToLower(s.NamePrefix))
+}
+
+// inScope reports whether r is covered by Provider.Scope, which all records
+// are if it isn't set.
+func (p *Provider) inScope(zone string, r libdns.Record) bool {
+	return p.Scope == nil || p.Scope.contains(normalizeRecordName(r.Name, zone))
+}
+
+// checkScope returns an error wrapping ErrOutOfScope if r isn't covered by
+// Provider.Scope.
+func (p *Provider) checkScope(zone string, r libdns.Record) error {
+	if !p.inScope(zone, r) {
+		return fmt.Errorf("%w: %s record %q", ErrOutOfScope, r.Type, r.Name)
+	}
+	return nil
+}
+
+// scoped returns the records covered by Provider.Scope.
+func (p *Provider) scoped(zone string, records []libdns.Record) []libdns.Record {
+	if p.Scope == nil {
+		return records
+	}
+
+	inScope := records[:0]
+	for _, r := range records {
+		if p.inScope(zone, r) {
+			inScope = append(inScope, r)
+		}
+	}
+	return inScope
+}
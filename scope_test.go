@@ -0,0 +1,39 @@
+package hetzner_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/libdns/hetzner"
+	"github.com/libdns/libdns"
+)
+
+func Test_Scope(t *testing.T) {
+	p, api := newFakeProvider(t, "A www 192.0.2.1", "A team-a 192.0.2.2", "A www.team-a 192.0.2.3", "A team-ab 192.0.2.4")
+	p.Scope = &hetzner.Scope{Subdomain: "team-a"}
+
+	records, err := p.GetRecords(context.TODO(), "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Name != "team-a" || records[1].Name != "www.team-a" {
+		t.Fatalf("unexpected records in scope => %v", records)
+	}
+
+	_, err = p.AppendRecords(context.TODO(), "example.com", []libdns.Record{{Type: "A", Name: "api", Value: "192.0.2.5"}})
+	if !errors.Is(err, hetzner.ErrOutOfScope) {
+		t.Fatalf("err != ErrOutOfScope => %v", err)
+	}
+	if _, err := p.AppendRecords(context.TODO(), "example.com", []libdns.Record{{Type: "A", Name: "api.team-a.example.com.", Value: "192.0.2.5"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = p.DeleteRecords(context.TODO(), "example.com", []libdns.Record{{ID: "r1", Type: "A", Name: "team-a", Value: "192.0.2.2"}})
+	if !errors.Is(err, hetzner.ErrRecordNotFound) {
+		t.Fatalf("err != ErrRecordNotFound => %v", err)
+	}
+	if records := api.dump("z"); len(records) != 5 {
+		t.Fatalf("unexpected records => %v", records)
+	}
+}
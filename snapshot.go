@@ -0,0 +1,82 @@
+package hetzner
+
+import (
+	"context"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// Snapshot is the state of a zone at a point in time, as taken by
+// SnapshotZone. It can be serialized, e.g. with encoding/json, and restored
+// with RestoreZone.
+type Snapshot struct {
+	// Zone is the name of the zone.
+	Zone string `json:"zone"`
+
+	// TTL is the default TTL of the zone.
+	TTL time.Duration `json:"ttl"`
+
+	// Taken is when the snapshot was taken.
+	Taken time.Time `json:"taken"`
+
+	// Records are all records of the zone, sorted as by GetRecords.
+	Records []libdns.Record `json:"records"`
+}
+
+// SnapshotZone takes a snapshot of the zone's records and settings.
+func (p *Provider) SnapshotZone(ctx context.Context, zone string) (Snapshot, error) {
+	zone = unFQDN(zone)
+
+	z, err := p.getZone(ctx, zone)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	records, err := p.GetRecords(ctx, zone)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	return Snapshot{
+		Zone:    zone,
+		TTL:     z.TTL,
+		Taken:   time.Now(),
+		Records: records,
+	}, nil
+}
+
+// RestoreZone reconciles the live zone back to the snapshot: its default TTL
+// is reset and its records are synced to the snapshot's records as by
+// SyncRecords, so records created since are deleted and changed or deleted
+// records are restored. Restored records get new IDs. With Provider.OwnerID
+// set, the snapshot's ownership registry records are ignored, as the sync
+// maintains the registry itself. It returns the plan that was applied to
+// the records.
+func (p *Provider) RestoreZone(ctx context.Context, snapshot Snapshot) (Plan, error) {
+	z, err := p.getZone(ctx, snapshot.Zone)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	if snapshot.TTL > 0 && snapshot.TTL != z.TTL {
+		if err := p.updateZoneTTL(ctx, z, snapshot.TTL); err != nil {
+			return Plan{}, err
+		}
+	}
+
+	// registry records are maintained along with the records they own
+	desired := p.withoutRegistryRecords(unFQDN(snapshot.Zone), snapshot.Records)
+
+	// records without a TTL of their own inherit the zone's TTL again,
+	// regardless of the provider's default TTLs
+	records := make([]libdns.Record, len(desired))
+	for i, r := range desired {
+		if r.TTL == 0 {
+			r.TTL = InheritZoneTTL
+		}
+		records[i] = r
+	}
+
+	return p.SyncRecords(ctx, snapshot.Zone, records)
+}
@@ -0,0 +1,80 @@
+package hetzner_test
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/libdns/hetzner"
+	"github.com/libdns/libdns"
+)
+
+func Test_SnapshotAndRestore(t *testing.T) {
+	p, api := newFakeProvider(t, "A www 192.0.2.1", "TXT @ hello")
+
+	snapshot, err := p.SnapshotZone(context.TODO(), "example.com.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.TTL != 24*time.Hour || len(snapshot.Records) != 2 {
+		t.Fatalf("unexpected snapshot => %+v", snapshot)
+	}
+
+	// the snapshot survives serialization
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var restored hetzner.Snapshot
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatal(err)
+	}
+
+	// a bad automation run
+	if _, err := p.SetRecords(context.TODO(), "example.com.", []libdns.Record{{Type: "A", Name: "www", Value: "192.0.2.99"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.AppendRecords(context.TODO(), "example.com.", []libdns.Record{{Type: "TXT", Name: "junk", Value: "junk"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := p.RestoreZone(context.TODO(), restored); err != nil {
+		t.Fatal(err)
+	}
+
+	records := api.dump("z")
+	slices.Sort(records)
+	if !slices.Equal(records, []string{"A www 192.0.2.1", "TXT @ hello"}) {
+		t.Fatalf("unexpected records after restore => %v", records)
+	}
+}
+
+func Test_RestoreZoneOwnership(t *testing.T) {
+	p, api := newFakeProvider(t)
+	p.OwnerID = "a"
+
+	if _, err := p.AppendRecords(context.TODO(), "example.com", []libdns.Record{{Type: "A", Name: "www", Value: "192.0.2.1"}}); err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err := p.SnapshotZone(context.TODO(), "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.AppendRecords(context.TODO(), "example.com", []libdns.Record{{Type: "TXT", Name: "junk", Value: "junk"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := p.RestoreZone(context.TODO(), snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Create) != 0 {
+		t.Fatalf("registry records were planned => %v", plan.Create)
+	}
+	records := api.dump("z")
+	if !slices.Equal(records, []string{"A www 192.0.2.1", "TXT _owner.a.www heritage=libdns-hetzner,owner=a"}) {
+		t.Fatalf("unexpected records after restore => %v", records)
+	}
+}
@@ -0,0 +1,106 @@
+package hetzner
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SOA is the value of a zone's SOA record.
+type SOA struct {
+	PrimaryNS  string
+	Mailbox    string
+	Serial     uint32
+	Refresh    uint32
+	Retry      uint32
+	Expire     uint32
+	MinimumTTL uint32
+}
+
+// ParseSOA parses an SOA record value like "hydrogen.ns.hetzner.com.
+// dns.hetzner.com. 2024010101 86400 10800 3600000 3600".
+func ParseSOA(value string) (SOA, error) {
+	fields := strings.Fields(value)
+	if len(fields) != 7 {
+		return SOA{}, fmt.Errorf("%q is not of the form \"<mname> <rname> <serial> <refresh> <retry> <expire> <minimum>\"", value)
+	}
+
+	numbers := make([]uint32, 5)
+	for i, field := range fields[2:] {
+		n, err := strconv.ParseUint(field, 10, 32)
+		if err != nil {
+			return SOA{}, fmt.Errorf("SOA field %q is not a 32 bit number", field)
+		}
+		numbers[i] = uint32(n)
+	}
+
+	return SOA{
+		PrimaryNS:  fields[0],
+		Mailbox:    fields[1],
+		Serial:     numbers[0],
+		Refresh:    numbers[1],
+		Retry:      numbers[2],
+		Expire:     numbers[3],
+		MinimumTTL: numbers[4],
+	}, nil
+}
+
+// GetSOA returns the zone's SOA record as published by Hetzner.
+func (p *Provider) GetSOA(ctx context.Context, zone string) (SOA, error) {
+	zone = unFQDN(zone)
+
+	records, err := p.getAllRecords(ctx, zone)
+	if err != nil {
+		return SOA{}, err
+	}
+
+	for _, record := range records {
+		if record.Type == "SOA" && normalizeRecordName(record.Name, zone) == "@" {
+			return ParseSOA(record.Value)
+		}
+	}
+	return SOA{}, fmt.Errorf("%w: no SOA record in zone %s", ErrRecordNotFound, zone)
+}
+
+// ZoneSerial returns the serial of the zone's SOA record. Hetzner bumps it
+// when it publishes changes to the zone.
+func (p *Provider) ZoneSerial(ctx context.Context, zone string) (uint32, error) {
+	soa, err := p.GetSOA(ctx, zone)
+	if err != nil {
+		return 0, err
+	}
+	return soa.Serial, nil
+}
+
+// WaitForSerial polls the zone's serial every interval until it is newer than
+// serial, e.g. one returned by ZoneSerial before making changes, and returns
+// the new serial. It returns early with the context's error when ctx is
+// done. Serials are compared using serial number arithmetic (RFC 1982), so
+// wrap-arounds are handled.
+func (p *Provider) WaitForSerial(ctx context.Context, zone string, serial uint32, interval time.Duration) (uint32, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		current, err := p.ZoneSerial(ctx, zone)
+		if err != nil {
+			return 0, err
+		}
+		if serialAfter(current, serial) {
+			return current, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// serialAfter reports whether serial a is newer than b per RFC 1982.
+func serialAfter(a uint32, b uint32) bool {
+	return a != b && int32(a-b) > 0
+}
@@ -0,0 +1,118 @@
+package hetzner
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// State is a desired-state document for a zone, as generated by ImportState
+// from the live zone. It serializes to JSON as e.g.
+//
+//	{"zone":"example.com","records":[{"name":"www","type":"A","value":"192.0.2.1","ttl":300}]}
+//
+// and can be applied with SyncRecords(ctx, state.Zone, state.LibdnsRecords()).
+type State struct {
+	Zone    string        `json:"zone"`
+	Records []StateRecord `json:"records"`
+}
+
+// StateRecord is a record of a State. Unlike libdns.Record it has no ID and
+// its TTL is in seconds; records without a TTL of their own follow the
+// zone's default TTL.
+type StateRecord struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+	TTL   int    `json:"ttl,omitempty"`
+}
+
+// LibdnsRecords returns the records of the state as libdns records.
+func (s State) LibdnsRecords() []libdns.Record {
+	records := make([]libdns.Record, 0, len(s.Records))
+	for _, r := range s.Records {
+		ttl := time.Duration(r.TTL) * time.Second
+		if ttl == 0 {
+			ttl = InheritZoneTTL
+		}
+		records = append(records, libdns.Record{Type: r.Type, Name: r.Name, Value: r.Value, TTL: ttl})
+	}
+	return records
+}
+
+// ImportState reads the live zone into a State, to bootstrap declarative
+// management of a zone from its current records. The SOA record, ownership
+// registry records and the records matching any of the exclude patterns are
+// left out.
+//
+// A pattern is a glob, see path.Match, for names relative to the zone, e.g.
+// "_acme-challenge*", optionally preceded by a record type and a space to
+// only exclude records of that type, e.g. "TXT *".
+func (p *Provider) ImportState(ctx context.Context, zone string, exclude []string) (State, error) {
+	zone = unFQDN(zone)
+
+	patterns, err := parseExcludePatterns(exclude)
+	if err != nil {
+		return State{}, err
+	}
+
+	records, err := p.GetRecords(ctx, zone)
+	if err != nil {
+		return State{}, err
+	}
+
+	state := State{Zone: zone, Records: []StateRecord{}}
+	for _, r := range p.withoutRegistryRecords(zone, records) {
+		if r.Type == "SOA" {
+			continue
+		}
+		name := normalizeRecordName(r.Name, zone)
+		if slices.ContainsFunc(patterns, func(pattern excludePattern) bool { return pattern.matches(r.Type, name) }) {
+			continue
+		}
+		state.Records = append(state.Records, StateRecord{
+			Name:  name,
+			Type:  r.Type,
+			Value: r.Value,
+			TTL:   int(r.TTL.Seconds()),
+		})
+	}
+
+	return state, nil
+}
+
+// excludePattern is a parsed exclude pattern of ImportState.
+type excludePattern struct {
+	recordType string
+	glob       string
+}
+
+func parseExcludePatterns(exclude []string) ([]excludePattern, error) {
+	patterns := make([]excludePattern, 0, len(exclude))
+	for _, s := range exclude {
+		pattern := excludePattern{glob: s}
+		if recordType, glob, ok := strings.Cut(s, " "); ok {
+			pattern = excludePattern{recordType: strings.ToUpper(recordType), glob: glob}
+		}
+		if _, err := path.Match(pattern.glob, ""); err != nil {
+			return nil, fmt.Errorf("exclude pattern %q: %w", s, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
+// matches reports whether the record with the given type and relative name
+// matches the pattern.
+func (pattern excludePattern) matches(recordType string, name string) bool {
+	if pattern.recordType != "" && pattern.recordType != recordType {
+		return false
+	}
+	match, _ := path.Match(pattern.glob, name)
+	return match
+}
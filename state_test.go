@@ -0,0 +1,36 @@
+package hetzner_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func Test_ImportState(t *testing.T) {
+	p, api := newFakeProvider(t, "A www 192.0.2.1", "TXT _acme-challenge token", "TXT @ v=spf1 -all", "A dev-api 192.0.2.2")
+	ttl := 300
+	api.records[0].TTL = &ttl
+
+	state, err := p.ImportState(context.TODO(), "example.com.", []string{"TXT _acme-challenge*", "dev-*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(state)
+	expected := `{"zone":"example.com","records":[{"name":"@","type":"TXT","value":"v=spf1 -all"},{"name":"www","type":"A","value":"192.0.2.1","ttl":300}]}`
+	if string(data) != expected {
+		t.Fatalf("%s != %s", data, expected)
+	}
+
+	// applying the imported state only deletes the excluded records
+	plan, err := p.PlanSync(context.TODO(), state.Zone, state.LibdnsRecords())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Create) != 0 || len(plan.Update) != 0 || len(plan.Delete) != 2 {
+		t.Fatalf("unexpected plan => %+v", plan)
+	}
+
+	if _, err := p.ImportState(context.TODO(), "example.com", []string{"["}); err == nil {
+		t.Fatal("malformed pattern was accepted")
+	}
+}
@@ -0,0 +1,74 @@
+package hetzner
+
+import (
+	"context"
+	"time"
+)
+
+// ZoneStats summarizes the records of a zone.
+type ZoneStats struct {
+	// Records is the number of records in the zone, including the SOA and
+	// apex NS records.
+	Records int
+
+	// Types is the number of records per record type.
+	Types map[string]int
+
+	// MinTTL, MaxTTL and AvgTTL are the lowest, highest and mean effective
+	// TTL of the records; records without a TTL of their own count with the
+	// zone's default TTL.
+	MinTTL time.Duration
+	MaxTTL time.Duration
+	AvgTTL time.Duration
+
+	// HasWildcard reports whether the zone has any wildcard records.
+	HasWildcard bool
+
+	// HasApex reports whether the zone has records at the apex other than
+	// the SOA and NS records, e.g. an A record for the bare domain.
+	HasApex bool
+}
+
+// ZoneStats returns statistics about the records of the zone.
+func (p *Provider) ZoneStats(ctx context.Context, zone string) (ZoneStats, error) {
+	zone = unFQDN(zone)
+
+	z, err := p.getZone(ctx, zone)
+	if err != nil {
+		return ZoneStats{}, err
+	}
+	records, err := p.getAllRecords(ctx, zone)
+	if err != nil {
+		return ZoneStats{}, err
+	}
+
+	stats := ZoneStats{Records: len(records), Types: map[string]int{}}
+	var total time.Duration
+	for i, r := range records {
+		stats.Types[r.Type]++
+
+		ttl := r.TTL
+		if ttl == 0 {
+			ttl = z.TTL
+		}
+		if i == 0 || ttl < stats.MinTTL {
+			stats.MinTTL = ttl
+		}
+		if ttl > stats.MaxTTL {
+			stats.MaxTTL = ttl
+		}
+		total += ttl
+
+		if IsWildcard(r.Name) {
+			stats.HasWildcard = true
+		}
+		if r.Type != "SOA" && r.Type != "NS" && normalizeRecordName(r.Name, zone) == "@" {
+			stats.HasApex = true
+		}
+	}
+	if len(records) > 0 {
+		stats.AvgTTL = total / time.Duration(len(records))
+	}
+
+	return stats, nil
+}
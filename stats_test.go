@@ -0,0 +1,27 @@
+package hetzner_test
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func Test_ZoneStats(t *testing.T) {
+	p, api := newFakeProvider(t, "NS @ hydrogen.ns.hetzner.com.", "A www 192.0.2.1", "A * 192.0.2.1", "TXT www hello")
+	ttl := 300
+	api.records[1].TTL = &ttl
+
+	stats, err := p.ZoneStats(context.TODO(), "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Records != 4 || stats.Types["A"] != 2 || stats.Types["NS"] != 1 || stats.Types["TXT"] != 1 {
+		t.Fatalf("unexpected counts => %+v", stats)
+	}
+	if stats.MinTTL != 5*time.Minute || stats.MaxTTL != 24*time.Hour || stats.AvgTTL != (5*time.Minute+3*24*time.Hour)/4 {
+		t.Fatalf("unexpected TTLs => %+v", stats)
+	}
+	if !stats.HasWildcard || stats.HasApex {
+		t.Fatalf("unexpected wildcard or apex => %+v", stats)
+	}
+}
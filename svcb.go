@@ -0,0 +1,190 @@
+package hetzner
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// SVCB is the value of an SVCB or HTTPS record (RFC 9460), e.g.
+// 1 . alpn=h2,h3 ipv4hint=192.0.2.1.
+//
+// Hetzner DNS does not accept SVCB and HTTPS records at the time of writing;
+// creating one fails with an APIError. The helpers format and validate
+// values, so callers can publish them as soon as the API supports them, or
+// with other providers.
+type SVCB struct {
+	// Priority is 0 for alias mode and the priority of the service
+	// otherwise.
+	Priority uint16
+
+	// Target is the target name; "." means the owner name itself.
+	Target string
+
+	// Params are the service parameters, in order.
+	Params []SVCParam
+}
+
+// SVCParam is a service parameter of an SVCB or HTTPS record, e.g. alpn with
+// value "h2,h3". Value is empty for parameters without a value like
+// no-default-alpn.
+type SVCParam struct {
+	Key   string
+	Value string
+}
+
+// String formats s as an SVCB or HTTPS record value. Parameter values
+// containing spaces or quotes are quoted.
+func (s SVCB) String() string {
+	var b strings.Builder
+	target := s.Target
+	if target == "" {
+		target = "."
+	}
+	fmt.Fprintf(&b, "%d %s", s.Priority, target)
+	for _, param := range s.Params {
+		b.WriteString(" " + param.Key)
+		if param.Value == "" {
+			continue
+		}
+		if strings.ContainsAny(param.Value, ` "`) {
+			value := strings.ReplaceAll(param.Value, `\`, `\\`)
+			value = strings.ReplaceAll(value, `"`, `\"`)
+			b.WriteString(`="` + value + `"`)
+		} else {
+			b.WriteString("=" + param.Value)
+		}
+	}
+	return b.String()
+}
+
+// Record returns a record of the given type, "SVCB" or "HTTPS", with s as
+// its value.
+func (s SVCB) Record(recordType string, name string, ttl time.Duration) libdns.Record {
+	return libdns.Record{Type: recordType, Name: name, Value: s.String(), TTL: ttl}
+}
+
+// Param returns the value of the parameter with the given key and whether it
+// is present.
+func (s SVCB) Param(key string) (string, bool) {
+	for _, param := range s.Params {
+		if param.Key == key {
+			return param.Value, true
+		}
+	}
+	return "", false
+}
+
+// ParseSVCB parses and validates an SVCB or HTTPS record value.
+func ParseSVCB(value string) (SVCB, error) {
+	fields, err := splitQuoted(value)
+	if err != nil {
+		return SVCB{}, err
+	}
+	if len(fields) < 2 {
+		return SVCB{}, fmt.Errorf("%q is not of the form \"<priority> <target> [<params>]\"", value)
+	}
+
+	priority, err := strconv.ParseUint(fields[0], 10, 16)
+	if err != nil {
+		return SVCB{}, fmt.Errorf("SVCB priority %q is not a number between 0 and 65535", fields[0])
+	}
+	s := SVCB{Priority: uint16(priority), Target: fields[1]}
+	if s.Target != "." {
+		if err := validateHostname(s.Target); err != nil {
+			return SVCB{}, err
+		}
+	}
+	if s.Priority == 0 && len(fields) > 2 {
+		return SVCB{}, fmt.Errorf("SVCB record in alias mode (priority 0) must not have parameters")
+	}
+
+	for _, field := range fields[2:] {
+		key, paramValue, _ := strings.Cut(field, "=")
+		param := SVCParam{Key: key, Value: paramValue}
+		if err := validateSVCParam(param); err != nil {
+			return SVCB{}, err
+		}
+		if _, ok := s.Param(key); ok {
+			return SVCB{}, fmt.Errorf("SVCB parameter %s is given more than once", key)
+		}
+		s.Params = append(s.Params, param)
+	}
+	return s, nil
+}
+
+func validateSVCParam(param SVCParam) error {
+	if param.Key == "" {
+		return fmt.Errorf("SVCB parameter key is empty")
+	}
+	for _, c := range param.Key {
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-') {
+			return fmt.Errorf("SVCB parameter key %q must consist of lowercase letters, digits and hyphens", param.Key)
+		}
+	}
+
+	switch param.Key {
+	case "port":
+		return validateUint16("SVCB port", param.Value)
+	case "ipv4hint":
+		for _, ip := range strings.Split(param.Value, ",") {
+			if err := validateIPv4(ip); err != nil {
+				return err
+			}
+		}
+	case "ipv6hint":
+		for _, ip := range strings.Split(param.Value, ",") {
+			if err := validateIPv6(ip); err != nil {
+				return err
+			}
+		}
+	case "alpn", "mandatory":
+		if param.Value == "" {
+			return fmt.Errorf("SVCB parameter %s requires a value", param.Key)
+		}
+	case "no-default-alpn":
+		if param.Value != "" {
+			return fmt.Errorf("SVCB parameter no-default-alpn takes no value")
+		}
+	}
+	return nil
+}
+
+// splitQuoted splits value at whitespace, keeping quoted parts of a field
+// together and removing their quotes.
+func splitQuoted(value string) ([]string, error) {
+	var fields []string
+	var field strings.Builder
+	inField, quoted, escaped := false, false, false
+	for _, c := range value {
+		switch {
+		case escaped:
+			field.WriteRune(c)
+			escaped = false
+		case c == '\\' && quoted:
+			escaped = true
+		case c == '"':
+			quoted = !quoted
+			inField = true
+		case (c == ' ' || c == '\t') && !quoted:
+			if inField {
+				fields = append(fields, field.String())
+				field.Reset()
+				inField = false
+			}
+		default:
+			field.WriteRune(c)
+			inField = true
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("%q has an unterminated quote", value)
+	}
+	if inField {
+		fields = append(fields, field.String())
+	}
+	return fields, nil
+}
@@ -0,0 +1,49 @@
+package hetzner_test
+
+import (
+	"testing"
+
+	"github.com/libdns/hetzner"
+)
+
+func Test_SVCB(t *testing.T) {
+	svcb := hetzner.SVCB{
+		Priority: 1,
+		Target:   ".",
+		Params: []hetzner.SVCParam{
+			{Key: "alpn", Value: "h2,h3"},
+			{Key: "port", Value: "8443"},
+			{Key: "ipv4hint", Value: "192.0.2.1,192.0.2.2"},
+			{Key: "key65000", Value: "a b"},
+		},
+	}
+
+	value := svcb.String()
+	if value != `1 . alpn=h2,h3 port=8443 ipv4hint=192.0.2.1,192.0.2.2 key65000="a b"` {
+		t.Fatalf("unexpected value => %s", value)
+	}
+
+	parsed, err := hetzner.ParseSVCB(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.String() != value {
+		t.Fatalf("%s != %s", parsed, value)
+	}
+	if alpn, ok := parsed.Param("alpn"); !ok || alpn != "h2,h3" {
+		t.Fatalf("alpn != h2,h3 => %q", alpn)
+	}
+
+	for _, invalid := range []string{
+		"0 . alpn=h2",
+		"1 . port=http",
+		"1 . ipv6hint=192.0.2.1",
+		"1 . alpn=h2 alpn=h3",
+		`1 . alpn="h2`,
+		"1",
+	} {
+		if _, err := hetzner.ParseSVCB(invalid); err == nil {
+			t.Fatalf("ParseSVCB(%q) succeeded", invalid)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package hetzner
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// Plan describes the changes needed to make a zone match a desired set of
+// records.
+type Plan struct {
+	// Create lists the records that will be created.
+	Create []libdns.Record
+
+	// Update lists existing records, by ID, with their new values.
+	Update []libdns.Record
+
+	// Delete lists the existing records that will be deleted.
+	Delete []libdns.Record
+}
+
+// Empty reports whether the plan contains no changes.
+func (p Plan) Empty() bool {
+	return p.Len() == 0
+}
+
+// Len returns the number of changes in the plan.
+func (p Plan) Len() int {
+	return len(p.Create) + len(p.Update) + len(p.Delete)
+}
+
+// PlanSync computes the changes SyncRecords would make to the zone for the
+// desired records, without applying them.
+func (p *Provider) PlanSync(ctx context.Context, zone string, desired []libdns.Record) (Plan, error) {
+	zone = unFQDN(zone)
+
+	current, err := p.getAllRecords(ctx, zone)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	if p.SyncOwnedOnly {
+		current, desired = p.ownedOnly(zone, current, desired)
+	}
+	current = p.withoutRegistryRecords(zone, current)
+	return planSync(zone, current, desired, p.AllowDangerous), nil
+}
+
+// SyncRecords reconciles the zone to contain exactly the desired records:
+// missing records are created, records whose value or TTL differ are
+// updated and all other records are deleted. The SOA record is never
+// touched, and neither are the apex NS records unless AllowDangerous is set.
+// It returns the plan that was applied, or the plan that was refused if it
+// exceeds Provider.MaxChangesPerApply or Provider.MaxRecordsPerZone.
+//
+// Records are created first and deleted last, each step following
+// Provider.BatchMode and Provider.Concurrency like SetRecords. If records of
+// a step fail, the later steps are skipped; the returned error joins a
+// RecordError for each failed or skipped record.
+func (p *Provider) SyncRecords(ctx context.Context, zone string, desired []libdns.Record) (Plan, error) {
+	plan, err := p.PlanSync(ctx, zone, desired)
+	if err != nil {
+		return Plan{}, err
+	}
+	if err := p.checkChangeLimit(plan); err != nil {
+		return plan, err
+	}
+	if err := p.checkQuota(ctx, unFQDN(zone), len(plan.Create)); err != nil {
+		return plan, err
+	}
+	ctx, err = p.withRegistry(ctx, unFQDN(zone))
+	if err != nil {
+		return plan, err
+	}
+
+	return plan, p.applyPlan(ctx, unFQDN(zone), plan)
+}
+
+// checkChangeLimit returns a *TooManyChangesError if the plan has more
+// changes than Provider.MaxChangesPerApply allows.
+func (p *Provider) checkChangeLimit(plan Plan) error {
+	if p.MaxChangesPerApply > 0 && plan.Len() > p.MaxChangesPerApply {
+		return &TooManyChangesError{Limit: p.MaxChangesPerApply, Plan: plan}
+	}
+	return nil
+}
+
+// planSet returns the changes SetRecords makes for records: records with an
+// ID or an existing record in index to claim are updates, the others are
+// created.
+func planSet(records []libdns.Record, index *recordIndex) Plan {
+	var plan Plan
+	for _, r := range records {
+		if len(r.ID) == 0 {
+			if existing := index.claim(r); existing != nil {
+				r.ID = existing.ID
+			}
+		}
+		if len(r.ID) == 0 {
+			plan.Create = append(plan.Create, r)
+		} else {
+			plan.Update = append(plan.Update, r)
+		}
+	}
+	return plan
+}
+
+// ApplyZoneFile parses a BIND zone file for the zone and reconciles the zone
+// to match it, see SyncRecords.
+func (p *Provider) ApplyZoneFile(ctx context.Context, zone string, r io.Reader) (Plan, error) {
+	desired, err := ParseZoneFile(r, zone)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	return p.SyncRecords(ctx, zone, desired)
+}
+
+// syncRRsets reconciles the records of the zone for which match returns true
+// to the desired records, like SyncRecords does for the whole zone. It
+// returns the plan that was applied.
+func (p *Provider) syncRRsets(ctx context.Context, zone string, desired []libdns.Record, match func(libdns.Record) bool) (Plan, error) {
+	records, err := p.getAllRecords(ctx, zone)
+	if err != nil {
+		return Plan{}, err
+	}
+	var current []libdns.Record
+	for _, r := range records {
+		if match(r) {
+			current = append(current, r)
+		}
+	}
+
+	plan := planSync(zone, current, desired, p.AllowDangerous)
+	if err := p.checkChangeLimit(plan); err != nil {
+		return plan, err
+	}
+	ctx, err = p.withRegistry(ctx, zone)
+	if err != nil {
+		return plan, err
+	}
+
+	return plan, p.applyPlan(ctx, zone, plan)
+}
+
+// applyPlan creates before it updates and updates before it deletes, so
+// names aren't left without records while the plan is applied. Each phase
+// is a batch operation like AppendRecords, following Provider.BatchMode and
+// Provider.Concurrency; if records of a phase fail, the later phases are
+// skipped and their records reported with ErrSkipped.
+func (p *Provider) applyPlan(ctx context.Context, zone string, plan Plan) error {
+	phases := []struct {
+		records []libdns.Record
+		apply   func(libdns.Record) (libdns.Record, error)
+	}{
+		{plan.Create, func(r libdns.Record) (libdns.Record, error) { return p.createRecord(ctx, zone, r) }},
+		{plan.Update, func(r libdns.Record) (libdns.Record, error) { return p.updateRecord(ctx, zone, r) }},
+		{plan.Delete, func(r libdns.Record) (libdns.Record, error) { return r, p.deleteRecord(ctx, zone, r) }},
+	}
+
+	var errs []error
+	for _, phase := range phases {
+		if len(errs) > 0 {
+			for _, r := range phase.records {
+				errs = append(errs, &RecordError{Record: r, Err: ErrSkipped})
+			}
+			continue
+		}
+		results := p.runBatch(ctx, zone, phase.records, phase.apply)
+		if err := p.batchError(ctx, "", zone, phase.records, results); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type rrsetKey struct {
+	name       string
+	recordType string
+}
+
+// planSync diffs current against desired per RRset. Within an RRset,
+// records with equal values are kept (or updated if the TTL changed),
+// surplus current records are reused for surplus desired values, and the
+// remainder is created or deleted.
+func planSync(zone string, current []libdns.Record, desired []libdns.Record, allowDangerous bool) Plan {
+	managed := func(r libdns.Record) bool {
+		if r.Type == "SOA" {
+			return false
+		}
+		return allowDangerous || !isApexNSOrSOA(r, zone)
+	}
+
+	currentSets := map[rrsetKey][]libdns.Record{}
+	desiredSets := map[rrsetKey][]libdns.Record{}
+	var keys []rrsetKey
+	add := func(sets map[rrsetKey][]libdns.Record, r libdns.Record) {
+		r.Name = normalizeRecordName(r.Name, zone)
+		key := rrsetKey{name: r.Name, recordType: r.Type}
+		if _, ok := currentSets[key]; !ok {
+			if _, ok := desiredSets[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sets[key] = append(sets[key], r)
+	}
+	for _, r := range current {
+		if managed(r) {
+			add(currentSets, r)
+		}
+	}
+	for _, r := range desired {
+		if managed(r) {
+			add(desiredSets, r)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].name != keys[j].name {
+			return keys[i].name < keys[j].name
+		}
+		return keys[i].recordType < keys[j].recordType
+	})
+
+	var plan Plan
+	for _, key := range keys {
+		existing := append([]libdns.Record(nil), currentSets[key]...)
+		var missing []libdns.Record
+
+		for _, want := range desiredSets[key] {
+			found := -1
+			for i, have := range existing {
+				if sameValue(have, want) {
+					found = i
+					break
+				}
+			}
+			if found < 0 {
+				missing = append(missing, want)
+				continue
+			}
+			have := existing[found]
+			existing = append(existing[:found], existing[found+1:]...)
+			if want.TTL != 0 && !sameTTL(want.TTL, have.TTL) {
+				want.ID = have.ID
+				plan.Update = append(plan.Update, want)
+			}
+		}
+
+		for _, want := range missing {
+			if len(existing) > 0 {
+				want.ID = existing[0].ID
+				existing = existing[1:]
+				plan.Update = append(plan.Update, want)
+				continue
+			}
+			want.ID = ""
+			plan.Create = append(plan.Create, want)
+		}
+		plan.Delete = append(plan.Delete, existing...)
+	}
+
+	return plan
+}
+
+// sameTTL reports whether the desired TTL want matches the TTL have of an
+// existing record, which is zero for records inheriting the zone's TTL.
+func sameTTL(want time.Duration, have time.Duration) bool {
+	if want == InheritZoneTTL {
+		return have == 0
+	}
+	return want == have
+}
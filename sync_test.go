@@ -0,0 +1,55 @@
+package hetzner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+func Test_planSync(t *testing.T) {
+	current := []libdns.Record{
+		{ID: "1", Type: "SOA", Name: "@", Value: "soa", TTL: time.Hour},
+		{ID: "2", Type: "NS", Name: "@", Value: "hydrogen.ns.hetzner.com.", TTL: time.Hour},
+		{ID: "3", Type: "A", Name: "www", Value: "192.0.2.1", TTL: time.Hour},
+		{ID: "4", Type: "A", Name: "www", Value: "192.0.2.2", TTL: time.Hour},
+		{ID: "5", Type: "TXT", Name: "old", Value: "old", TTL: time.Hour},
+		{ID: "6", Type: "A", Name: "ttl", Value: "192.0.2.1", TTL: time.Hour},
+	}
+	desired := []libdns.Record{
+		{Type: "A", Name: "www.example.com.", Value: "192.0.2.1"},
+		{Type: "A", Name: "www", Value: "192.0.2.3"},
+		{Type: "A", Name: "ttl", Value: "192.0.2.1", TTL: time.Minute},
+		{Type: "TXT", Name: "new", Value: "new"},
+	}
+
+	plan := planSync("example.com", current, desired, false)
+
+	expectedCreate := []libdns.Record{
+		{Type: "TXT", Name: "new", Value: "new"},
+	}
+	expectedUpdate := []libdns.Record{
+		{ID: "6", Type: "A", Name: "ttl", Value: "192.0.2.1", TTL: time.Minute},
+		{ID: "4", Type: "A", Name: "www", Value: "192.0.2.3"},
+	}
+	expectedDelete := []libdns.Record{
+		{ID: "5", Type: "TXT", Name: "old", Value: "old", TTL: time.Hour},
+	}
+
+	assertRecords(t, "create", plan.Create, expectedCreate)
+	assertRecords(t, "update", plan.Update, expectedUpdate)
+	assertRecords(t, "delete", plan.Delete, expectedDelete)
+}
+
+func assertRecords(t *testing.T, what string, records []libdns.Record, expected []libdns.Record) {
+	t.Helper()
+
+	if len(records) != len(expected) {
+		t.Fatalf("%s: len(records) != len(expected) => %d != %d: %+v", what, len(records), len(expected), records)
+	}
+	for i := range expected {
+		if records[i] != expected[i] {
+			t.Fatalf("%s: records[%d] != expected[%d] => %+v != %+v", what, i, i, records[i], expected[i])
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package hetzner
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// RecordTemplate describes records to generate for every host of a host
+// list, see ExpandTemplates. Its fields are text/template templates executed
+// with the host's fields, e.g. {Name: "{{.host}}", Type: "A", Value:
+// "{{.ipv4}}"}.
+type RecordTemplate struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+
+	// TTL renders to a duration like "5m" or a number of seconds. An empty
+	// TTL leaves the record's TTL unset.
+	TTL string `json:"ttl,omitempty"`
+}
+
+// ExpandTemplates generates the records of all templates for every host, in
+// order, e.g. to feed a host inventory into SyncRecords. Records whose value
+// renders to an empty string are skipped, so a template for AAAA records can
+// be used for a host list in which only some hosts have IPv6 addresses.
+func ExpandTemplates(templates []RecordTemplate, hosts []map[string]string) ([]libdns.Record, error) {
+	type parsed struct {
+		name, recordType, value, ttl *template.Template
+	}
+
+	parsedTemplates := make([]parsed, 0, len(templates))
+	for i, t := range templates {
+		var pt parsed
+		for _, f := range []struct {
+			dst  **template.Template
+			text string
+		}{{&pt.name, t.Name}, {&pt.recordType, t.Type}, {&pt.value, t.Value}, {&pt.ttl, t.TTL}} {
+			tmpl, err := template.New("").Option("missingkey=error").Parse(f.text)
+			if err != nil {
+				return nil, fmt.Errorf("record template %d: %w", i, err)
+			}
+			*f.dst = tmpl
+		}
+		parsedTemplates = append(parsedTemplates, pt)
+	}
+
+	var records []libdns.Record
+	for _, host := range hosts {
+		for i, pt := range parsedTemplates {
+			var fields [4]string
+			for j, tmpl := range []*template.Template{pt.name, pt.recordType, pt.value, pt.ttl} {
+				var b strings.Builder
+				if err := tmpl.Execute(&b, host); err != nil {
+					return nil, fmt.Errorf("record template %d: %w", i, err)
+				}
+				fields[j] = strings.TrimSpace(b.String())
+			}
+			if fields[2] == "" {
+				continue
+			}
+
+			ttl, err := parseTemplateTTL(fields[3])
+			if err != nil {
+				return nil, fmt.Errorf("record template %d: %w", i, err)
+			}
+			records = append(records, libdns.Record{
+				Name:  fields[0],
+				Type:  strings.ToUpper(fields[1]),
+				Value: fields[2],
+				TTL:   ttl,
+			})
+		}
+	}
+
+	return records, nil
+}
+
+func parseTemplateTTL(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if seconds, err := strconv.Atoi(s); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	ttl, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid TTL %q", s)
+	}
+	return ttl, nil
+}
+
+// ReadHostsCSV reads a host list for ExpandTemplates from CSV. The first row
+// names the fields, e.g. "host,ipv4,ipv6".
+func ReadHostsCSV(r io.Reader) ([]map[string]string, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	header := rows[0]
+	hosts := make([]map[string]string, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		host := make(map[string]string, len(header))
+		for i, field := range header {
+			host[strings.TrimSpace(field)] = strings.TrimSpace(row[i])
+		}
+		hosts = append(hosts, host)
+	}
+	return hosts, nil
+}
+
+// ReadHostsJSON reads a host list for ExpandTemplates from a JSON array of
+// objects with string values, e.g. [{"host":"web1","ipv4":"192.0.2.1"}].
+func ReadHostsJSON(r io.Reader) ([]map[string]string, error) {
+	var hosts []map[string]string
+	if err := json.NewDecoder(r).Decode(&hosts); err != nil {
+		return nil, err
+	}
+	return hosts, nil
+}
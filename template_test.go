@@ -0,0 +1,49 @@
+package hetzner_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/libdns/hetzner"
+	"github.com/libdns/libdns"
+)
+
+func Test_ExpandTemplates(t *testing.T) {
+	hosts, err := hetzner.ReadHostsCSV(strings.NewReader("host,ipv4,ipv6\nweb1,192.0.2.1,2001:db8::1\nweb2,192.0.2.2,\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsonHosts, err := hetzner.ReadHostsJSON(strings.NewReader(`[{"host":"web1","ipv4":"192.0.2.1","ipv6":"2001:db8::1"},{"host":"web2","ipv4":"192.0.2.2","ipv6":""}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	templates := []hetzner.RecordTemplate{
+		{Name: "{{.host}}", Type: "A", Value: "{{.ipv4}}", TTL: "5m"},
+		{Name: "{{.host}}", Type: "aaaa", Value: "{{.ipv6}}", TTL: "300"},
+	}
+	expected := []libdns.Record{
+		{Name: "web1", Type: "A", Value: "192.0.2.1", TTL: 5 * time.Minute},
+		{Name: "web1", Type: "AAAA", Value: "2001:db8::1", TTL: 5 * time.Minute},
+		{Name: "web2", Type: "A", Value: "192.0.2.2", TTL: 5 * time.Minute},
+	}
+	for _, h := range [][]map[string]string{hosts, jsonHosts} {
+		records, err := hetzner.ExpandTemplates(templates, h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != len(expected) {
+			t.Fatalf("%v != %v", records, expected)
+		}
+		for i := range records {
+			if records[i] != expected[i] {
+				t.Fatalf("%v != %v", records[i], expected[i])
+			}
+		}
+	}
+
+	if _, err := hetzner.ExpandTemplates([]hetzner.RecordTemplate{{Name: "{{.name}}", Type: "A", Value: "{{.ip}}"}}, hosts); err == nil {
+		t.Fatal("missing host field was accepted")
+	}
+}
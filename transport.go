@@ -0,0 +1,70 @@
+package hetzner
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+)
+
+// httpClient returns the client for API requests: HTTPClient if set,
+// otherwise a client using Transport, or a default transport routed through
+// ProxyURL and tuned with the connection settings if set. Without any of
+// these the default transport is used, which honors the HTTP_PROXY,
+// HTTPS_PROXY and NO_PROXY environment variables.
+func (p *Provider) httpClient() *http.Client {
+	if p.HTTPClient != nil {
+		return p.HTTPClient
+	}
+
+	p.clientOnce.Do(func() {
+		transport := p.Transport
+		if transport == nil && p.customTransport() {
+			transport = p.defaultTransport()
+		}
+		p.client = &http.Client{Transport: transport}
+	})
+	return p.client
+}
+
+// customTransport reports whether the default transport needs to be
+// adjusted with ProxyURL or the connection tuning settings.
+func (p *Provider) customTransport() bool {
+	return p.ProxyURL != "" || p.MaxIdleConnsPerHost != 0 || p.MaxConnsPerHost != 0 || p.IdleConnTimeout != 0 || p.DisableHTTP2
+}
+
+// defaultTransport returns a clone of the default transport adjusted with
+// ProxyURL and the connection tuning settings.
+func (p *Provider) defaultTransport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if p.ProxyURL != "" {
+		// Validate has checked the URL before the first request.
+		proxy, _ := url.Parse(p.ProxyURL)
+		t.Proxy = http.ProxyURL(proxy)
+	}
+	if p.MaxIdleConnsPerHost > 0 {
+		t.MaxIdleConnsPerHost = p.MaxIdleConnsPerHost
+		if t.MaxIdleConns < p.MaxIdleConnsPerHost {
+			t.MaxIdleConns = p.MaxIdleConnsPerHost
+		}
+	}
+	if p.MaxConnsPerHost > 0 {
+		t.MaxConnsPerHost = p.MaxConnsPerHost
+	}
+	if p.IdleConnTimeout > 0 {
+		t.IdleConnTimeout = p.IdleConnTimeout
+	}
+	if p.DisableHTTP2 {
+		// a non-nil empty map keeps the transport from upgrading to HTTP/2
+		t.ForceAttemptHTTP2 = false
+		t.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	return t
+}
+
+// WithTransport sets the round tripper used for API requests, e.g. one that
+// adds mTLS client certificates for an API gateway.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(p *Provider) {
+		p.Transport = transport
+	}
+}
@@ -0,0 +1,88 @@
+package hetzner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_httpClientTuning(t *testing.T) {
+	p := &Provider{MaxIdleConnsPerHost: 64, IdleConnTimeout: time.Minute}
+
+	transport, ok := p.httpClient().Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("unexpected transport %T", p.httpClient().Transport)
+	}
+	if transport.MaxIdleConnsPerHost != 64 || transport.MaxIdleConns < 64 {
+		t.Fatalf("MaxIdleConnsPerHost != 64 => %d (MaxIdleConns %d)", transport.MaxIdleConnsPerHost, transport.MaxIdleConns)
+	}
+	if transport.IdleConnTimeout != time.Minute {
+		t.Fatalf("IdleConnTimeout != 1m => %s", transport.IdleConnTimeout)
+	}
+
+	if (&Provider{}).httpClient().Transport != nil {
+		t.Fatal("expected the default transport without tuning")
+	}
+}
+
+func Test_httpClientDisableHTTP2(t *testing.T) {
+	p := &Provider{DisableHTTP2: true}
+
+	transport, ok := p.httpClient().Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("unexpected transport %T", p.httpClient().Transport)
+	}
+	if transport.ForceAttemptHTTP2 || transport.TLSNextProto == nil || len(transport.TLSNextProto) != 0 {
+		t.Fatalf("HTTP/2 not disabled => ForceAttemptHTTP2 %t, TLSNextProto %v", transport.ForceAttemptHTTP2, transport.TLSNextProto)
+	}
+}
+
+func Test_httpClientProxyURL(t *testing.T) {
+	var requested []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.Method+" "+r.URL.String())
+		fmt.Fprint(w, `{"zones":[{"id":"z","name":"example.com"}]}`)
+	}))
+	defer proxy.Close()
+
+	p := New("token", WithBaseURL("http://dns.example.invalid/api/v1"))
+	p.ProxyURL = proxy.URL
+
+	zones, err := p.ListZones(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(zones) != 1 || len(requested) != 1 || !strings.HasPrefix(requested[0], "GET http://dns.example.invalid/api/v1/zones?") {
+		t.Fatalf("request not sent through the proxy => %v, %v", requested, zones)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func Test_httpClientTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"zones":[{"id":"z","name":"example.com"}]}`)
+	}))
+	defer server.Close()
+
+	var calls int
+	p := New("token", WithBaseURL(server.URL), WithTransport(roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		calls++
+		return http.DefaultTransport.RoundTrip(r)
+	})))
+
+	if _, err := p.ListZones(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Fatalf("calls != 1 => %d", calls)
+	}
+}
@@ -0,0 +1,85 @@
+package hetzner
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// MinTTL is the lowest TTL the Hetzner DNS API accepts.
+const MinTTL = 60 * time.Second
+
+// MaxTTL is the highest TTL that fits the API's 32-bit TTL field.
+const MaxTTL = math.MaxInt32 * time.Second
+
+// InheritZoneTTL can be used as a record's TTL to send the record without a
+// TTL, so it follows the zone's default TTL even if
+// Provider.DefaultRecordTTL is set.
+const InheritZoneTTL time.Duration = -1
+
+// TTLPolicy decides what happens to records with a TTL below MinTTL or
+// above MaxTTL.
+type TTLPolicy int
+
+const (
+	// TTLPolicyError rejects records with a TTL below MinTTL or above MaxTTL
+	// with an error wrapping ErrInvalidTTL, before any request is made.
+	TTLPolicyError TTLPolicy = iota
+
+	// TTLPolicyClamp raises TTLs below MinTTL to MinTTL, lowers TTLs above
+	// MaxTTL to MaxTTL and logs a warning.
+	TTLPolicyClamp
+)
+
+// apiTTL returns the TTL of r in seconds as sent to the API, applying the
+// provider's default TTLs and TTL policy. A nil result means the TTL is
+// omitted and the record inherits the zone's default TTL. Negative TTLs other
+// than InheritZoneTTL are always rejected.
+func (p *Provider) apiTTL(r libdns.Record) (*int, error) {
+	if r.TTL == InheritZoneTTL {
+		return nil, nil
+	}
+
+	ttl := r.TTL
+	if ttl == 0 {
+		ttl = p.TypeTTLs[r.Type]
+	}
+	if ttl == 0 {
+		ttl = p.DefaultRecordTTL
+	}
+	if ttl == 0 {
+		return nil, nil
+	}
+
+	if ttl < 0 {
+		return nil, fmt.Errorf("%w: %s record %q has negative TTL %s", ErrInvalidTTL, r.Type, r.Name, ttl)
+	}
+	if ttl > MaxTTL {
+		if p.TTLPolicy != TTLPolicyClamp {
+			return nil, fmt.Errorf("%w: %s record %q has TTL %s, maximum is %s", ErrInvalidTTL, r.Type, r.Name, ttl, MaxTTL)
+		}
+		p.logf("clamping TTL of %s record %q from %s to %s", r.Type, r.Name, ttl, MaxTTL)
+		ttl = MaxTTL
+	}
+	if ttl < MinTTL {
+		if p.TTLPolicy != TTLPolicyClamp {
+			return nil, fmt.Errorf("%w: %s record %q has TTL %s, minimum is %s", ErrInvalidTTL, r.Type, r.Name, ttl, MinTTL)
+		}
+		p.logf("clamping TTL of %s record %q from %s to %s", r.Type, r.Name, ttl, MinTTL)
+		ttl = MinTTL
+	}
+
+	seconds := int(ttl.Seconds())
+	return &seconds, nil
+}
+
+// ttlDuration converts a TTL as returned by the API to a duration. Records
+// without a TTL of their own have a zero TTL.
+func ttlDuration(ttl *int) time.Duration {
+	if ttl == nil {
+		return 0
+	}
+	return time.Duration(*ttl) * time.Second
+}
@@ -0,0 +1,60 @@
+package hetzner
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+func Test_apiTTL(t *testing.T) {
+	p := &Provider{
+		DefaultRecordTTL: time.Hour,
+		TypeTTLs:         map[string]time.Duration{"TXT": time.Minute, "MX": 24 * time.Hour},
+	}
+
+	testCases := []struct {
+		record   libdns.Record
+		expected int
+	}{
+		{record: libdns.Record{Type: "A"}, expected: 3600},
+		{record: libdns.Record{Type: "TXT"}, expected: 60},
+		{record: libdns.Record{Type: "MX"}, expected: 86400},
+		{record: libdns.Record{Type: "TXT", TTL: 5 * time.Minute}, expected: 300},
+		{record: libdns.Record{Type: "TXT", TTL: InheritZoneTTL}, expected: 0},
+	}
+
+	for _, c := range testCases {
+		ttl, err := p.apiTTL(c.record)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := 0
+		if ttl != nil {
+			got = *ttl
+		}
+		if got != c.expected {
+			t.Fatalf("%s record with TTL %s: %d != %d", c.record.Type, c.record.TTL, got, c.expected)
+		}
+	}
+
+	if _, err := p.apiTTL(libdns.Record{Type: "A", TTL: time.Second}); !errors.Is(err, ErrInvalidTTL) {
+		t.Fatalf("err != ErrInvalidTTL => %v", err)
+	}
+
+	for _, ttl := range []time.Duration{-time.Minute, MaxTTL + time.Second} {
+		if _, err := p.apiTTL(libdns.Record{Type: "A", TTL: ttl}); !errors.Is(err, ErrInvalidTTL) {
+			t.Fatalf("TTL %s: err != ErrInvalidTTL => %v", ttl, err)
+		}
+	}
+
+	p.TTLPolicy = TTLPolicyClamp
+	if ttl, err := p.apiTTL(libdns.Record{Type: "A", TTL: 100 * 365 * 24 * time.Hour}); err != nil || *ttl != math.MaxInt32 {
+		t.Fatalf("clamped TTL != MaxInt32 => %v, %v", ttl, err)
+	}
+	if _, err := p.apiTTL(libdns.Record{Type: "A", TTL: -time.Minute}); !errors.Is(err, ErrInvalidTTL) {
+		t.Fatalf("negative TTL: err != ErrInvalidTTL => %v", err)
+	}
+}
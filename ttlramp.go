@@ -0,0 +1,105 @@
+package hetzner
+
+import (
+	"cmp"
+	"context"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// TTLRampOptions configures ChangeWithTTLRamp.
+type TTLRampOptions struct {
+	// LowTTL is the TTL the RRset is lowered to before the change. Defaults
+	// to MinTTL.
+	LowTTL time.Duration
+
+	// Wait waits out the given TTL. It defaults to sleeping until the
+	// duration passed or ctx is done, and can be replaced e.g. to report
+	// progress.
+	Wait func(ctx context.Context, d time.Duration) error
+}
+
+// ChangeWithTTLRamp changes the values of the RRset of name and recordType
+// to values without resolvers holding on to the old values for long: the
+// RRset's TTL is lowered to LowTTL first, the old TTL is waited out so
+// caches only hold the low TTL, the values are changed, and finally the
+// original TTL is restored. Records without a TTL of their own count with the
+// zone's default TTL and inherit it again afterwards.
+//
+// The RRset is left with the low TTL if the call fails or ctx is done
+// midway.
+func (p *Provider) ChangeWithTTLRamp(ctx context.Context, zone string, name string, recordType string, values []string, opts TTLRampOptions) error {
+	zone = unFQDN(zone)
+
+	low := opts.LowTTL
+	if low == 0 {
+		low = MinTTL
+	}
+	wait := opts.Wait
+	if wait == nil {
+		wait = sleep
+	}
+	inRRset := func(r libdns.Record) bool {
+		return r.Type == recordType && sameName(r.Name, name, zone)
+	}
+
+	z, err := p.getZone(ctx, zone)
+	if err != nil {
+		return err
+	}
+	records, err := p.getAllRecords(ctx, zone)
+	if err != nil {
+		return err
+	}
+
+	var current []libdns.Record
+	var original, effective time.Duration
+	for _, r := range records {
+		if !inRRset(r) {
+			continue
+		}
+		current = append(current, libdns.Record{Type: r.Type, Name: r.Name, Value: r.Value, TTL: low})
+		original = max(original, r.TTL)
+		effective = max(effective, cmp.Or(r.TTL, z.TTL))
+	}
+	if original == 0 {
+		original = InheritZoneTTL
+	}
+
+	if effective > low {
+		if _, err := p.syncRRsets(ctx, zone, current, inRRset); err != nil {
+			return err
+		}
+		if err := wait(ctx, effective); err != nil {
+			return err
+		}
+	}
+
+	desired := make([]libdns.Record, 0, len(values))
+	for _, value := range values {
+		desired = append(desired, libdns.Record{Type: recordType, Name: name, Value: value, TTL: low})
+	}
+	if _, err := p.syncRRsets(ctx, zone, desired, inRRset); err != nil {
+		return err
+	}
+
+	for i := range desired {
+		desired[i].TTL = original
+	}
+	_, err = p.syncRRsets(ctx, zone, desired, inRRset)
+	return err
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,41 @@
+package hetzner_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/libdns/hetzner"
+)
+
+func Test_ChangeWithTTLRamp(t *testing.T) {
+	p, api := newFakeProvider(t, "A www 192.0.2.1", "A mail 192.0.2.1")
+	ttl := 3600
+	api.records[0].TTL = &ttl
+
+	var waited []time.Duration
+	opts := hetzner.TTLRampOptions{Wait: func(ctx context.Context, d time.Duration) error {
+		waited = append(waited, d)
+		if records, _ := p.GetRecords(ctx, "example.com"); records[1].TTL != hetzner.MinTTL || records[1].Value != "192.0.2.1" {
+			t.Fatalf("record wasn't lowered before waiting => %v", records[1])
+		}
+		return nil
+	}}
+	if err := p.ChangeWithTTLRamp(context.TODO(), "example.com", "www", "A", []string{"192.0.2.2"}, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(waited) != 1 || waited[0] != time.Hour {
+		t.Fatalf("unexpected waits => %v", waited)
+	}
+	records, err := p.GetRecords(context.TODO(), "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := records[1]; r.Name != "www" || r.Value != "192.0.2.2" || r.TTL != time.Hour {
+		t.Fatalf("unexpected record after ramp => %v", r)
+	}
+	if r := records[0]; r.Value != "192.0.2.1" || r.TTL != 0 {
+		t.Fatalf("other record was changed => %v", r)
+	}
+}
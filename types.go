@@ -0,0 +1,42 @@
+package hetzner
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/libdns/libdns"
+)
+
+// ErrUnsupportedType is returned with Provider.StrictTypes for records of a
+// type Hetzner DNS doesn't support.
+var ErrUnsupportedType = errors.New("record type not supported by Hetzner DNS")
+
+// supportedRecordTypes are the record types the Hetzner DNS API accepts, see
+// https://dns.hetzner.com/api-docs#operation/CreateRecord.
+var supportedRecordTypes = []string{
+	"A", "AAAA", "CAA", "CNAME", "DANE", "DS", "HINFO", "MX", "NS", "PTR", "RP", "SOA", "SRV", "TLSA", "TXT",
+}
+
+// checkType returns an error wrapping ErrUnsupportedType if Provider.StrictTypes
+// is set and Hetzner DNS doesn't support the type of r.
+func (p *Provider) checkType(r libdns.Record) error {
+	if !p.StrictTypes || p.Supports(r.Type) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s record %q", ErrUnsupportedType, r.Type, r.Name)
+}
+
+// SupportedRecordTypes returns the record types the provider's API supports,
+// in alphabetical order, so generic tooling can adapt to it. The provider
+// only talks to the Hetzner DNS Console API, whose types these are.
+func (p *Provider) SupportedRecordTypes() []string {
+	return slices.Clone(supportedRecordTypes)
+}
+
+// Supports reports whether the provider's API supports records of the given
+// type, compared case-insensitively, see SupportedRecordTypes.
+func (p *Provider) Supports(recordType string) bool {
+	return slices.Contains(supportedRecordTypes, strings.ToUpper(recordType))
+}
@@ -0,0 +1,43 @@
+package hetzner_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/libdns/hetzner"
+	"github.com/libdns/libdns"
+)
+
+func Test_SupportedRecordTypes(t *testing.T) {
+	p := &hetzner.Provider{}
+
+	types := p.SupportedRecordTypes()
+	if !slices.Contains(types, "CAA") || slices.Contains(types, "SVCB") {
+		t.Fatalf("unexpected types => %v", types)
+	}
+	types[0] = "SVCB"
+	if p.Supports("SVCB") {
+		t.Fatal("Supports(SVCB) after modifying the result")
+	}
+	if !p.Supports("aaaa") {
+		t.Fatal("!Supports(aaaa)")
+	}
+}
+
+func Test_StrictTypes(t *testing.T) {
+	p, api := newFakeProvider(t)
+	p.StrictTypes = true
+
+	_, err := p.AppendRecords(context.TODO(), "example.com", []libdns.Record{
+		{Type: "TXT", Name: "@", Value: "test"},
+		{Type: "SVCB", Name: "_svc", Value: "1 svc.example.com."},
+	})
+	if !errors.Is(err, hetzner.ErrUnsupportedType) {
+		t.Fatalf("err != ErrUnsupportedType => %v", err)
+	}
+	if records := api.dump("z"); len(records) != 1 || records[0] != "TXT @ test" {
+		t.Fatalf("unexpected records => %v", records)
+	}
+}
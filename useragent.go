@@ -0,0 +1,46 @@
+package hetzner
+
+import (
+	"runtime/debug"
+	"sync"
+)
+
+// modulePath is the import path of this module, used to find its version in
+// the build info of the binary.
+const modulePath = "github.com/libdns/hetzner"
+
+var (
+	versionOnce sync.Once
+	version     = "devel"
+)
+
+// Version returns the version of this package as recorded in the build info
+// of the running binary, or "devel" if it is unknown.
+func Version() string {
+	versionOnce.Do(func() {
+		info, ok := debug.ReadBuildInfo()
+		if !ok {
+			return
+		}
+		if info.Main.Path == modulePath && info.Main.Version != "" && info.Main.Version != "(devel)" {
+			version = info.Main.Version
+			return
+		}
+		for _, dep := range info.Deps {
+			if dep.Path == modulePath {
+				version = dep.Version
+				return
+			}
+		}
+	})
+	return version
+}
+
+// userAgent returns the User-Agent header sent with API requests.
+func (p *Provider) userAgent() string {
+	ua := "libdns-hetzner/" + Version()
+	if p.UserAgent != "" {
+		ua += " " + p.UserAgent
+	}
+	return ua
+}
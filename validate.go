@@ -0,0 +1,136 @@
+package hetzner
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Validate checks the provider's configuration and returns an error
+// describing every problem found, each wrapping ErrInvalidConfig. It is
+// called automatically before the first API request.
+func (p *Provider) Validate() error {
+	var errs []error
+	invalid := func(format string, v ...interface{}) {
+		errs = append(errs, fmt.Errorf("%w: "+format, append([]interface{}{ErrInvalidConfig}, v...)...))
+	}
+
+	switch {
+	case p.AuthAPIToken == "":
+		invalid("AuthAPIToken is empty; create an API token at https://dns.hetzner.com/settings/api-token")
+	case strings.TrimSpace(p.AuthAPIToken) != p.AuthAPIToken:
+		invalid("AuthAPIToken has leading or trailing whitespace; check how the token is read from the environment or config file")
+	}
+
+	if p.baseURL != "" {
+		u, err := url.Parse(p.baseURL)
+		if err != nil {
+			invalid("base URL %q is malformed: %v", p.baseURL, err)
+		} else if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			invalid("base URL %q must be an absolute http or https URL", p.baseURL)
+		}
+	}
+
+	if p.HTTPClient != nil && p.Transport != nil {
+		invalid("both HTTPClient and Transport are set; set the transport on the HTTP client instead")
+	}
+	if p.ProxyURL != "" {
+		u, err := url.Parse(p.ProxyURL)
+		if err != nil {
+			invalid("ProxyURL %q is malformed: %v", p.ProxyURL, err)
+		} else if u.Host == "" {
+			invalid("ProxyURL %q has no host; use a URL like http://proxy:3128", p.ProxyURL)
+		}
+		if p.HTTPClient != nil || p.Transport != nil {
+			invalid("ProxyURL is ignored because HTTPClient or Transport is set; configure the proxy there")
+		}
+	}
+	if p.MaxIdleConnsPerHost < 0 || p.MaxConnsPerHost < 0 || p.IdleConnTimeout < 0 {
+		invalid("MaxIdleConnsPerHost, MaxConnsPerHost and IdleConnTimeout must not be negative")
+	}
+	if (p.HTTPClient != nil || p.Transport != nil) && (p.MaxIdleConnsPerHost != 0 || p.MaxConnsPerHost != 0 || p.IdleConnTimeout != 0 || p.DisableHTTP2) {
+		invalid("connection tuning settings are ignored because HTTPClient or Transport is set; configure the transport there")
+	}
+
+	if p.Retry != nil {
+		r := p.Retry
+		if r.MaxAttempts < 0 {
+			invalid("Retry.MaxAttempts is negative (%d); use 0 or 1 to disable retries", r.MaxAttempts)
+		}
+		if r.BaseDelay < 0 || r.MaxDelay < 0 {
+			invalid("Retry.BaseDelay and Retry.MaxDelay must not be negative")
+		}
+		if r.BaseDelay > 0 && r.MaxDelay > 0 && r.MaxDelay < r.BaseDelay {
+			invalid("Retry.MaxDelay (%s) is lower than Retry.BaseDelay (%s)", r.MaxDelay, r.BaseDelay)
+		}
+		if r.Jitter < 0 || r.Jitter > 1 {
+			invalid("Retry.Jitter must be between 0 and 1, got %v", r.Jitter)
+		}
+		for _, code := range r.RetryableStatusCodes {
+			if code < 100 || code > 599 {
+				invalid("Retry.RetryableStatusCodes contains invalid HTTP status code %d", code)
+			}
+		}
+	}
+
+	if p.Policy != nil {
+		if err := p.Policy.validate(); err != nil {
+			invalid("Policy is malformed: %v", err)
+		}
+	}
+	if p.SyncOwnedOnly && p.OwnerID == "" {
+		invalid("SyncOwnedOnly requires OwnerID to tell the provider's records apart")
+	}
+	if p.MaxRecordsPerZone < 0 {
+		invalid("MaxRecordsPerZone is negative (%d); use 0 for no limit", p.MaxRecordsPerZone)
+	}
+	if p.MaxChangesPerApply < 0 {
+		invalid("MaxChangesPerApply is negative (%d); use 0 for no limit", p.MaxChangesPerApply)
+	}
+	if p.BulkChunkSize < 0 {
+		invalid("BulkChunkSize is negative (%d)", p.BulkChunkSize)
+	}
+	if p.Concurrency < 0 {
+		invalid("Concurrency is negative (%d)", p.Concurrency)
+	}
+	if p.BreakerThreshold < 0 || p.BreakerCooldown < 0 {
+		invalid("BreakerThreshold and BreakerCooldown must not be negative")
+	}
+	if p.ZoneLookupTimeout < 0 || p.ReadTimeout < 0 || p.WriteTimeout < 0 {
+		invalid("ZoneLookupTimeout, ReadTimeout and WriteTimeout must not be negative")
+	}
+	if p.DefaultRecordTTL < 0 {
+		invalid("DefaultRecordTTL is negative (%s)", p.DefaultRecordTTL)
+	}
+	if p.DefaultRecordTTL > MaxTTL {
+		invalid("DefaultRecordTTL exceeds MaxTTL (%s)", p.DefaultRecordTTL)
+	}
+	for recordType, ttl := range p.TypeTTLs {
+		if ttl < 0 {
+			invalid("TypeTTLs[%q] is negative (%s)", recordType, ttl)
+		}
+		if ttl > MaxTTL {
+			invalid("TypeTTLs[%q] exceeds MaxTTL (%s)", recordType, ttl)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateOnce runs Validate until it succeeds and remembers the success,
+// so a provider whose configuration is fixed after a failed request works
+// from then on.
+func (p *Provider) validateOnce() error {
+	p.validation.Lock()
+	defer p.validation.Unlock()
+
+	if p.validated {
+		return nil
+	}
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	p.validated = true
+	return nil
+}
@@ -0,0 +1,150 @@
+package hetzner
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/libdns/libdns"
+)
+
+// ErrInvalidRecord is wrapped by the errors returned for records whose value
+// is not valid for their type. Such records are rejected before any request
+// is made.
+var ErrInvalidRecord = errors.New("invalid record")
+
+// valueValidators check record values by record type. Types without an entry
+// are passed to the API unchecked.
+var valueValidators = map[string]func(value string) error{
+	"A":     validateIPv4,
+	"AAAA":  validateIPv6,
+	"CNAME": validateHostname,
+	"NS":    validateHostname,
+	"MX":    validateMX,
+	"SRV":   validateSRV,
+	"CAA":   func(value string) error { _, err := ParseCAA(value); return err },
+	"TLSA":  func(value string) error { _, err := ParseTLSA(value); return err },
+	"DS":    func(value string) error { _, err := ParseDS(value); return err },
+	"SSHFP": func(value string) error { _, err := ParseSSHFP(value); return err },
+	"SVCB":  func(value string) error { _, err := ParseSVCB(value); return err },
+	"HTTPS": func(value string) error { _, err := ParseSVCB(value); return err },
+}
+
+// ValidateRecord checks that the record's value is valid for its type, e.g.
+// that an A record holds an IPv4 address. It returns an error wrapping
+// ErrInvalidRecord otherwise.
+func ValidateRecord(r libdns.Record) error {
+	validate, ok := valueValidators[r.Type]
+	if !ok {
+		return nil
+	}
+	if err := validate(r.Value); err != nil {
+		return fmt.Errorf("%w: %s record %q: %v", ErrInvalidRecord, r.Type, r.Name, err)
+	}
+	return nil
+}
+
+// canonicalValue returns value, of a record of the given type, in the form it
+// is written and compared in: A and AAAA addresses are formatted as in
+// RFC 5952, e.g. "2001:db8::1" for "2001:0db8::0001". Other values, and
+// addresses that don't parse, are returned unchanged.
+func canonicalValue(recordType string, value string) string {
+	if recordType != "A" && recordType != "AAAA" {
+		return value
+	}
+	addr, err := netip.ParseAddr(value)
+	if err != nil || addr.Zone() != "" {
+		return value
+	}
+	return addr.Unmap().String()
+}
+
+// sameValue reports whether records a and b, which have the same type, have
+// the same value in canonical form. Host names in CNAME, NS, PTR, MX and SRV
+// values match with or without a trailing dot, see Diff.
+func sameValue(a libdns.Record, b libdns.Record) bool {
+	return diffValue(a) == diffValue(b)
+}
+
+func validateIPv4(value string) error {
+	addr, err := netip.ParseAddr(value)
+	if err != nil || !addr.Is4() {
+		return fmt.Errorf("%q is not an IPv4 address", value)
+	}
+	return nil
+}
+
+func validateIPv6(value string) error {
+	addr, err := netip.ParseAddr(value)
+	if err != nil || !addr.Is6() || addr.Is4In6() {
+		return fmt.Errorf("%q is not an IPv6 address", value)
+	}
+	return nil
+}
+
+// validateHostname checks the syntax of a host name, relative or fully
+// qualified. Underscores are allowed, as they are common in service labels.
+func validateHostname(value string) error {
+	name := strings.TrimSuffix(value, ".")
+	if name == "" || len(name) > 253 {
+		return fmt.Errorf("%q is not a valid host name", value)
+	}
+	for _, label := range strings.Split(name, ".") {
+		if len(label) == 0 || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return fmt.Errorf("%q is not a valid host name", value)
+		}
+		for _, c := range label {
+			if !isLetterOrDigit(c) && c != '-' && c != '_' {
+				return fmt.Errorf("%q is not a valid host name", value)
+			}
+		}
+	}
+	return nil
+}
+
+func validateMX(value string) error {
+	fields := strings.Fields(value)
+	if len(fields) != 2 {
+		return fmt.Errorf("%q is not of the form \"<preference> <host>\"", value)
+	}
+	if err := validateUint16("preference", fields[0]); err != nil {
+		return err
+	}
+	// a null MX (RFC 7505) announces that the domain accepts no mail
+	if fields[1] == "." {
+		if preference, _ := strconv.Atoi(fields[0]); preference != 0 {
+			return fmt.Errorf("%q is a null MX with a preference other than 0", value)
+		}
+		return nil
+	}
+	return validateHostname(fields[1])
+}
+
+func validateSRV(value string) error {
+	fields := strings.Fields(value)
+	if len(fields) != 4 {
+		return fmt.Errorf("%q is not of the form \"<priority> <weight> <port> <target>\"", value)
+	}
+	for i, field := range []string{"priority", "weight", "port"} {
+		if err := validateUint16(field, fields[i]); err != nil {
+			return err
+		}
+	}
+	if fields[3] == "." {
+		return nil
+	}
+	return validateHostname(fields[3])
+}
+
+func validateUint16(field string, value string) error {
+	if _, err := strconv.ParseUint(value, 10, 16); err != nil {
+		return fmt.Errorf("%s %q is not a number between 0 and 65535", field, value)
+	}
+	return nil
+}
+
+func isLetterOrDigit(c rune) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
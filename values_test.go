@@ -0,0 +1,84 @@
+package hetzner_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/libdns/hetzner"
+	"github.com/libdns/libdns"
+)
+
+func Test_ValidateRecord(t *testing.T) {
+	testCases := []struct {
+		recordType string
+		value      string
+		valid      bool
+	}{
+		{recordType: "A", value: "192.0.2.1", valid: true},
+		{recordType: "A", value: "2001:db8::1", valid: false},
+		{recordType: "A", value: "192.0.2", valid: false},
+		{recordType: "AAAA", value: "2001:db8::1", valid: true},
+		{recordType: "AAAA", value: "192.0.2.1", valid: false},
+		{recordType: "CNAME", value: "target.example.com.", valid: true},
+		{recordType: "CNAME", value: "-bad.example.com", valid: false},
+		{recordType: "CNAME", value: "a..b", valid: false},
+		{recordType: "NS", value: "hydrogen.ns.hetzner.com.", valid: true},
+		{recordType: "MX", value: "10 mail.example.com.", valid: true},
+		{recordType: "MX", value: "mail.example.com.", valid: false},
+		{recordType: "MX", value: "0 .", valid: true},
+		{recordType: "MX", value: "10 .", valid: false},
+		{recordType: "SRV", value: "10 5 5060 sip.example.com.", valid: true},
+		{recordType: "SRV", value: "10 5 70000 sip.example.com.", valid: false},
+		{recordType: "CAA", value: `0 issue "letsencrypt.org"`, valid: true},
+		{recordType: "CAA", value: `0 is-sue "letsencrypt.org"`, valid: false},
+		{recordType: "CAA", value: `256 issue "letsencrypt.org"`, valid: false},
+		{recordType: "TXT", value: "anything goes", valid: true},
+	}
+
+	for _, c := range testCases {
+		err := hetzner.ValidateRecord(libdns.Record{Type: c.recordType, Name: "test", Value: c.value})
+		if c.valid && err != nil {
+			t.Fatalf("%s %q: unexpected error => %v", c.recordType, c.value, err)
+		}
+		if !c.valid && !errors.Is(err, hetzner.ErrInvalidRecord) {
+			t.Fatalf("%s %q: err != ErrInvalidRecord => %v", c.recordType, c.value, err)
+		}
+	}
+}
+
+func Test_CanonicalAddresses(t *testing.T) {
+	p, api := newFakeProvider(t, "AAAA www 2001:db8::1")
+
+	if _, err := p.SetRecords(context.TODO(), "example.com", []libdns.Record{
+		{Type: "AAAA", Name: "www", Value: "2001:0db8::0001"},
+		{Type: "AAAA", Name: "mail", Value: "2001:DB8:0:0:0:0:0:2"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if records := api.dump("z"); len(records) != 2 || records[0] != "AAAA www 2001:db8::1" || records[1] != "AAAA mail 2001:db8::2" {
+		t.Fatalf("unexpected records => %v", records)
+	}
+
+	if _, err := p.LookupRecordID(context.TODO(), "example.com", "www", "AAAA", "2001:0db8:0000::1"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_TrailingDotMatching(t *testing.T) {
+	p, api := newFakeProvider(t, "CNAME www target.example.org.", "MX @ 10 mail.example.org.")
+
+	if _, err := p.SetRecords(context.TODO(), "example.com", []libdns.Record{
+		{Type: "CNAME", Name: "www", Value: "target.example.org"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.DeleteRecords(context.TODO(), "example.com", []libdns.Record{
+		{Type: "MX", Name: "@", Value: "10 mail.example.org"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if records := api.dump("z"); len(records) != 1 || records[0] != "CNAME www target.example.org" {
+		t.Fatalf("unexpected records => %v", records)
+	}
+}
@@ -0,0 +1,380 @@
+package hetzner
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// ZoneMetadata describes the zone a zone file is rendered for.
+type ZoneMetadata struct {
+	// Origin is the zone name, emitted as the $ORIGIN directive.
+	Origin string
+
+	// TTL is the default TTL, emitted as the $TTL directive. Records without
+	// a TTL inherit it. It is omitted if zero.
+	TTL time.Duration
+}
+
+// WriteZoneFile renders records as a BIND zone file relative to meta.Origin.
+// The SOA record comes first and the rest are sorted by name, type and value,
+// so rendering the same records twice yields identical output.
+func WriteZoneFile(w io.Writer, meta ZoneMetadata, records []libdns.Record) error {
+	origin := unFQDN(meta.Origin)
+
+	sorted := make([]libdns.Record, len(records))
+	copy(sorted, records)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if (a.Type == "SOA") != (b.Type == "SOA") {
+			return a.Type == "SOA"
+		}
+		an, bn := normalizeRecordName(a.Name, origin), normalizeRecordName(b.Name, origin)
+		if an != bn {
+			if an == "@" || bn == "@" {
+				return an == "@"
+			}
+			return an < bn
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.Value < b.Value
+	})
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "$ORIGIN %s.\n", origin)
+	if meta.TTL > 0 {
+		fmt.Fprintf(bw, "$TTL %d\n", int(meta.TTL.Seconds()))
+	}
+
+	for _, r := range sorted {
+		ttl := ""
+		if r.TTL > 0 {
+			ttl = fmt.Sprintf("%d", int(r.TTL.Seconds()))
+		}
+		fmt.Fprintf(bw, "%s\t%s\tIN\t%s\t%s\n", normalizeRecordName(r.Name, origin), ttl, r.Type, zoneFileValue(r))
+	}
+
+	return bw.Flush()
+}
+
+// maxTXTChunk is the maximum length of a character string in a TXT record.
+const maxTXTChunk = 255
+
+// zoneFileValue returns the record's value in zone file presentation format.
+// Hetzner stores TXT values with or without surrounding quotes; unquoted
+// values are quoted here, split into strings of at most 255 bytes, the
+// longest a DNS character string can be.
+func zoneFileValue(r libdns.Record) string {
+	if r.Type != "TXT" || strings.HasPrefix(r.Value, `"`) {
+		return r.Value
+	}
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	quote := func(s string) string { return `"` + escaper.Replace(s) + `"` }
+
+	var chunks []string
+	value := r.Value
+	for len(value) > maxTXTChunk {
+		chunks = append(chunks, quote(value[:maxTXTChunk]))
+		value = value[maxTXTChunk:]
+	}
+	return strings.Join(append(chunks, quote(value)), " ")
+}
+
+// ParseZoneFile reads a BIND zone file for the given zone and returns its
+// records with names relative to the zone. $ORIGIN and $TTL directives,
+// parenthesized multi-line records, omitted owners and omitted classes are
+// supported; $INCLUDE and $GENERATE are not. Records without a TTL and
+// without a preceding $TTL directive have a zero TTL.
+func ParseZoneFile(r io.Reader, zone string) ([]libdns.Record, error) {
+	zone = strings.ToLower(unFQDN(zone))
+	p := zoneFileParser{origin: zone, zone: zone}
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	var pending []string
+	depth := 0
+	startLine := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		tokens, delta, err := tokenizeZoneFileLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNo, err)
+		}
+		if depth == 0 {
+			startLine = lineNo
+			pending = tokens
+			if len(line) > 0 && (line[0] == ' ' || line[0] == '\t') {
+				// the owner is omitted, mark it with an empty token
+				pending = append([]string{""}, tokens...)
+			}
+		} else {
+			pending = append(pending, tokens...)
+		}
+		depth += delta
+		if depth < 0 {
+			return nil, fmt.Errorf("line %d: unbalanced parentheses", lineNo)
+		}
+		if depth > 0 {
+			continue
+		}
+		if err := p.parseEntry(pending); err != nil {
+			return nil, fmt.Errorf("line %d: %v", startLine, err)
+		}
+		pending = nil
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if depth > 0 {
+		return nil, fmt.Errorf("line %d: unbalanced parentheses", startLine)
+	}
+
+	return p.records, nil
+}
+
+type zoneFileParser struct {
+	zone       string
+	origin     string
+	defaultTTL time.Duration
+	lastOwner  string
+	records    []libdns.Record
+}
+
+// parseEntry parses the tokens of one logical zone file line. A leading
+// empty token means the line started with whitespace, i.e. the owner was
+// omitted.
+func (p *zoneFileParser) parseEntry(tokens []string) error {
+	if len(tokens) == 0 || (len(tokens) == 1 && tokens[0] == "") {
+		return nil
+	}
+
+	switch strings.ToUpper(tokens[0]) {
+	case "$ORIGIN":
+		if len(tokens) != 2 {
+			return fmt.Errorf("$ORIGIN takes exactly one argument")
+		}
+		origin, err := p.absoluteName(tokens[1])
+		if err != nil {
+			return err
+		}
+		p.origin = origin
+		return nil
+	case "$TTL":
+		if len(tokens) != 2 {
+			return fmt.Errorf("$TTL takes exactly one argument")
+		}
+		ttl, ok := parseZoneFileTTL(tokens[1])
+		if !ok {
+			return fmt.Errorf("invalid TTL %q", tokens[1])
+		}
+		p.defaultTTL = ttl
+		return nil
+	case "$INCLUDE", "$GENERATE":
+		return fmt.Errorf("%s is not supported", tokens[0])
+	}
+
+	owner := tokens[0]
+	if owner == "" {
+		if p.lastOwner == "" {
+			return fmt.Errorf("record without owner name")
+		}
+		owner = p.lastOwner
+	} else {
+		abs, err := p.absoluteName(owner)
+		if err != nil {
+			return err
+		}
+		owner = abs
+	}
+	p.lastOwner = owner
+	fields := tokens[1:]
+
+	ttl := p.defaultTTL
+	for len(fields) > 0 {
+		if v, ok := parseZoneFileTTL(fields[0]); ok {
+			ttl = v
+		} else if c := strings.ToUpper(fields[0]); c == "IN" || c == "CH" || c == "HS" {
+			if c != "IN" {
+				return fmt.Errorf("unsupported class %s", c)
+			}
+		} else {
+			break
+		}
+		fields = fields[1:]
+	}
+	if len(fields) == 0 {
+		return fmt.Errorf("missing record type")
+	}
+
+	recordType := strings.ToUpper(fields[0])
+	if !slices.Contains(supportedRecordTypes, recordType) {
+		return fmt.Errorf("record type %s is not supported by Hetzner DNS", fields[0])
+	}
+	if len(fields) < 2 {
+		return fmt.Errorf("missing value for %s record", recordType)
+	}
+
+	name, err := p.relativeName(owner)
+	if err != nil {
+		return err
+	}
+
+	p.records = append(p.records, libdns.Record{
+		Type:  recordType,
+		Name:  name,
+		Value: zoneFileRdata(recordType, fields[1:]),
+		TTL:   ttl,
+	})
+	return nil
+}
+
+// absoluteName resolves a zone file name against the current origin. The
+// result is lowercase and has no trailing dot.
+func (p *zoneFileParser) absoluteName(name string) (string, error) {
+	if name == "@" {
+		return p.origin, nil
+	}
+	if strings.HasSuffix(name, ".") {
+		return strings.ToLower(unFQDN(name)), nil
+	}
+	return strings.ToLower(name + "." + p.origin), nil
+}
+
+// relativeName returns the name of an absolute owner relative to the zone.
+func (p *zoneFileParser) relativeName(abs string) (string, error) {
+	if abs == p.zone {
+		return "@", nil
+	}
+	if !strings.HasSuffix(abs, "."+p.zone) {
+		return "", fmt.Errorf("name %s is outside of zone %s", abs, p.zone)
+	}
+	return strings.TrimSuffix(abs, "."+p.zone), nil
+}
+
+// zoneFileRdata joins the rdata tokens into a record value. The quoted
+// character strings of a TXT record are unquoted and concatenated, which
+// turns the strings zoneFileValue splits long values into back into the
+// value.
+func zoneFileRdata(recordType string, fields []string) string {
+	if recordType != "TXT" || !allQuoted(fields) {
+		return strings.Join(fields, " ")
+	}
+	unescaper := strings.NewReplacer(`\"`, `"`, `\\`, `\`)
+	var value strings.Builder
+	for _, field := range fields {
+		value.WriteString(unescaper.Replace(field[1 : len(field)-1]))
+	}
+	return value.String()
+}
+
+// allQuoted reports whether every field is a quoted string.
+func allQuoted(fields []string) bool {
+	for _, field := range fields {
+		if len(field) < 2 || field[0] != '"' || field[len(field)-1] != '"' {
+			return false
+		}
+	}
+	return len(fields) > 0
+}
+
+// parseZoneFileTTL parses a TTL given in seconds or in BIND's unit notation
+// (e.g. "1h30m").
+func parseZoneFileTTL(s string) (time.Duration, bool) {
+	if s == "" || s[0] < '0' || s[0] > '9' {
+		return 0, false
+	}
+
+	var total, n time.Duration
+	hasUnit := false
+	for _, c := range strings.ToLower(s) {
+		switch {
+		case c >= '0' && c <= '9':
+			n = n*10 + time.Duration(c-'0')
+			continue
+		case c == 's':
+			total += n * time.Second
+		case c == 'm':
+			total += n * time.Minute
+		case c == 'h':
+			total += n * time.Hour
+		case c == 'd':
+			total += n * 24 * time.Hour
+		case c == 'w':
+			total += n * 7 * 24 * time.Hour
+		default:
+			return 0, false
+		}
+		hasUnit = true
+		n = 0
+	}
+	if !hasUnit {
+		return n * time.Second, true
+	}
+	return total + n*time.Second, true
+}
+
+// tokenizeZoneFileLine splits a zone file line into tokens, dropping comments
+// and parentheses. Quoted strings are kept as single tokens including their
+// quotes. It also returns the net change in parenthesis depth.
+func tokenizeZoneFileLine(line string) ([]string, int, error) {
+	var tokens []string
+	depth := 0
+	var current strings.Builder
+	inQuotes := false
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		if inQuotes {
+			current.WriteByte(c)
+			if c == '\\' && i+1 < len(line) {
+				i++
+				current.WriteByte(line[i])
+			} else if c == '"' {
+				inQuotes = false
+				flush()
+			}
+			continue
+		}
+
+		switch c {
+		case ';':
+			flush()
+			return tokens, depth, nil
+		case ' ', '\t':
+			flush()
+		case '(':
+			flush()
+			depth++
+		case ')':
+			flush()
+			depth--
+		case '"':
+			flush()
+			inQuotes = true
+			current.WriteByte(c)
+		default:
+			current.WriteByte(c)
+		}
+	}
+	if inQuotes {
+		return nil, 0, fmt.Errorf("unterminated quoted string")
+	}
+	flush()
+
+	return tokens, depth, nil
+}
@@ -0,0 +1,130 @@
+package hetzner
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+func Test_WriteZoneFile(t *testing.T) {
+	records := []libdns.Record{
+		{Type: "TXT", Name: "_acme-challenge", Value: `say "hi"`, TTL: 120 * time.Second},
+		{Type: "A", Name: "www.example.com.", Value: "192.0.2.1"},
+		{Type: "A", Name: "@", Value: "192.0.2.1", TTL: time.Hour},
+		{Type: "SOA", Name: "@", Value: "hydrogen.ns.hetzner.com. dns.hetzner.com. 2020080302 86400 10800 3600000 3600"},
+	}
+
+	var buf bytes.Buffer
+	err := WriteZoneFile(&buf, ZoneMetadata{Origin: "example.com.", TTL: 86400 * time.Second}, records)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "$ORIGIN example.com.\n" +
+		"$TTL 86400\n" +
+		"@\t\tIN\tSOA\thydrogen.ns.hetzner.com. dns.hetzner.com. 2020080302 86400 10800 3600000 3600\n" +
+		"@\t3600\tIN\tA\t192.0.2.1\n" +
+		"_acme-challenge\t120\tIN\tTXT\t\"say \\\"hi\\\"\"\n" +
+		"www\t\tIN\tA\t192.0.2.1\n"
+	if buf.String() != expected {
+		t.Fatalf("unexpected zone file:\n%s\nexpected:\n%s", buf.String(), expected)
+	}
+}
+
+func Test_WriteZoneFileLongTXT(t *testing.T) {
+	key := strings.Repeat("MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8AMIIBCgKCAQEA", 10)
+	value := "v=DKIM1; k=rsa; p=" + key
+	records := []libdns.Record{{Type: "TXT", Name: "mail._domainkey", Value: value}}
+
+	var buf bytes.Buffer
+	if err := WriteZoneFile(&buf, ZoneMetadata{Origin: "example.com."}, records); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "mail._domainkey\t\tIN\tTXT\t\"" + value[:255] + "\" \"" + value[255:] + "\"\n"
+	if !strings.HasSuffix(buf.String(), expected) {
+		t.Fatalf("unexpected zone file:\n%s\nexpected:\n%s", buf.String(), expected)
+	}
+}
+
+func Test_ParseZoneFile(t *testing.T) {
+	zoneFile := `$ORIGIN example.com.
+$TTL 1h
+@	IN	SOA	hydrogen.ns.hetzner.com. dns.hetzner.com. (
+		2020080302 ; serial
+		86400 10800 3600000 3600 )
+@		IN	NS	hydrogen.ns.hetzner.com.
+www	300	IN	A	192.0.2.1
+	IN	300	AAAA	2001:db8::1
+Mail.Example.Com.	MX	10 mail
+_acme-challenge	IN	TXT	"say \"hi\"" ; comment
+$ORIGIN sub.example.com.
+txt	1d	TXT	"a" "b"
+`
+
+	records, err := ParseZoneFile(strings.NewReader(zoneFile), "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []libdns.Record{
+		{Type: "SOA", Name: "@", Value: "hydrogen.ns.hetzner.com. dns.hetzner.com. 2020080302 86400 10800 3600000 3600", TTL: time.Hour},
+		{Type: "NS", Name: "@", Value: "hydrogen.ns.hetzner.com.", TTL: time.Hour},
+		{Type: "A", Name: "www", Value: "192.0.2.1", TTL: 300 * time.Second},
+		{Type: "AAAA", Name: "www", Value: "2001:db8::1", TTL: 300 * time.Second},
+		{Type: "MX", Name: "mail", Value: "10 mail", TTL: time.Hour},
+		{Type: "TXT", Name: "_acme-challenge", Value: `say "hi"`, TTL: time.Hour},
+		{Type: "TXT", Name: "txt.sub", Value: "ab", TTL: 24 * time.Hour},
+	}
+	if len(records) != len(expected) {
+		t.Fatalf("len(records) != len(expected) => %d != %d", len(records), len(expected))
+	}
+	for i := range expected {
+		if records[i] != expected[i] {
+			t.Fatalf("records[%d] != expected[%d] => %+v != %+v", i, i, records[i], expected[i])
+		}
+	}
+}
+
+func Test_ZoneFileRoundTrip(t *testing.T) {
+	records := []libdns.Record{
+		{Type: "TXT", Name: "mail._domainkey", Value: "v=DKIM1; k=rsa; p=" + strings.Repeat("a", 300) + `\"end"`, TTL: time.Hour},
+		{Type: "TXT", Name: "short", Value: `say "hi"`, TTL: time.Hour},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteZoneFile(&buf, ZoneMetadata{Origin: "example.com."}, records); err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseZoneFile(&buf, "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed) != len(records) {
+		t.Fatalf("len(parsed) != len(records) => %v", parsed)
+	}
+	for i := range records {
+		if parsed[i] != records[i] {
+			t.Fatalf("parsed[%d] != records[%d] => %+v != %+v", i, i, parsed[i], records[i])
+		}
+	}
+}
+
+func Test_ParseZoneFileErrors(t *testing.T) {
+	testCases := []string{
+		"www IN SPF \"v=spf1 -all\"",
+		"www.example.org. IN A 192.0.2.1",
+		"www IN A (192.0.2.1",
+		"$INCLUDE other.zone",
+		"  IN A 192.0.2.1",
+	}
+
+	for _, c := range testCases {
+		if _, err := ParseZoneFile(strings.NewReader(c), "example.com"); err == nil {
+			t.Fatalf("expected error for %q", c)
+		}
+	}
+}